@@ -0,0 +1,135 @@
+package validata
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts lists the layouts accepted for dates written as strings, in values and rule parameters.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+var relativeDateRgx = regexp.MustCompile(`^([+-])(\d+)([hdwmy])$`)
+
+// dateOf returns the date held by value, a time.Time or a string in one of the date layouts.
+func dateOf(value reflect.Value, loc *time.Location) (time.Time, bool) {
+	value = indirect(value)
+	if !value.IsValid() {
+		return time.Time{}, false
+	}
+	if value.Type() == timeType {
+		return value.Interface().(time.Time), true
+	}
+	if value.Kind() != reflect.String {
+		return time.Time{}, false
+	}
+	return parseDate(value.String(), loc)
+}
+
+func parseDate(date string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, date, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dateReference returns the date given by a rule parameter: now, today, tomorrow, yesterday,
+// a date relative to today such as +30d, -2w, +6m or +1y (+3h is relative to now),
+// a literal date or the date held by the field it references. field reports whether it references a field.
+func (c *RuleContext) dateReference(param string) (date time.Time, field, ok bool) {
+	now := c.validation.validator.clock()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch param {
+	case "now":
+		return now, false, true
+	case "today":
+		return today, false, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), false, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), false, true
+	}
+	if matches := relativeDateRgx.FindStringSubmatch(param); matches != nil {
+		n, _ := strconv.Atoi(matches[2])
+		if matches[1] == "-" {
+			n = -n
+		}
+		switch matches[3] {
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), false, true
+		case "d":
+			return today.AddDate(0, 0, n), false, true
+		case "w":
+			return today.AddDate(0, 0, 7*n), false, true
+		case "m":
+			return today.AddDate(0, n, 0), false, true
+		}
+		return today.AddDate(n, 0, 0), false, true
+	}
+	if date, ok := parseDate(param, now.Location()); ok {
+		return date, false, true
+	}
+	date, ok = dateOf(c.Lookup(param), now.Location())
+	return date, true, ok
+}
+
+// compareDate returns a rule comparing the date held by the value with the date given by its parameter.
+// The rule fails when the value is not a date and passes when the parameter refers to a field without a date.
+func compareDate(ok func(c int) bool) RuleFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) bool {
+		date, valid := dateOf(value, ctx.validation.validator.clock().Location())
+		if !valid {
+			return false
+		}
+		other, _, resolved := ctx.dateReference(param(params, 0))
+		if !resolved {
+			return true
+		}
+		return ok(date.Compare(other))
+	}
+}
+
+// dateMessage renders the parameter of a date rule: the name of the field it refers to, the literal date
+// it gives or the date it resolves to, such as 2024-03-10 for today.
+func dateMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) (string, []string) {
+		date, field, ok := ctx.dateReference(param(params, 0))
+		switch {
+		case field:
+			return key, []string{referenceName(param(params, 0))}
+		case !ok:
+			return key, params
+		}
+		if _, literal := parseDate(param(params, 0), date.Location()); literal {
+			return key, params
+		}
+		if date.Hour() == 0 && date.Minute() == 0 && date.Second() == 0 {
+			return key, []string{date.Format("2006-01-02")}
+		}
+		return key, []string{date.Format("2006-01-02 15:04")}
+	}
+}
+
+// isNotDateFormat reports whether v, a string, does not match layout.
+func isNotDateFormat(v reflect.Value, layout string) bool {
+	_, err := time.Parse(layout, v.String())
+	return err != nil
+}
+
+// dateLayout returns the layout of a date_format rule, whose commas were split into params.
+func dateLayout(params []string) string {
+	return strings.Join(params, ",")
+}
@@ -0,0 +1,159 @@
+package validata
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// IndexStyle selects how slice indexes are written in flattened error paths.
+type IndexStyle int
+
+const (
+	// IndexDot writes indexes as path segments, e.g. contacts.0.email.
+	IndexDot IndexStyle = iota
+	// IndexBracket writes indexes in brackets, e.g. contacts[0].email.
+	IndexBracket
+)
+
+type flatFormat struct {
+	separator string
+	style     IndexStyle
+}
+
+// FieldError describes a single rule that failed during validation.
+type FieldError struct {
+	// Field is the full path of the field, e.g. contacts.0.email.
+	Field string `json:"field"`
+	// Key is the JSON key of the field, e.g. email.
+	Key string `json:"key"`
+	// Rule is the name of the failed rule, e.g. min.
+	Rule string `json:"rule"`
+	// Params holds the rule parameters, e.g. ["6"] for min:6.
+	Params []string `json:"params,omitempty"`
+	// Message is the rendered (or custom) error message.
+	Message string `json:"message"`
+	// Locale is the locale the message was rendered in.
+	Locale string `json:"locale"`
+
+	path fieldPath
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors is returned by Validate when one or more fields fail validation.
+// Use errors.As to retrieve it from the returned error.
+type ValidationErrors struct {
+	errors []*FieldError
+	flat   *flatFormat
+}
+
+func newValidationErrors(errMsgs []*FieldError, flat *flatFormat) error {
+	if len(errMsgs) == 0 {
+		return nil
+	}
+	return &ValidationErrors{errors: errMsgs, flat: flat}
+}
+
+// Error implements the error interface.
+func (e *ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e.errors))
+	for _, fieldErr := range e.errors {
+		msgs = append(msgs, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Has reports whether the field at path, or any field nested under it, failed validation.
+func (e *ValidationErrors) Has(path string) bool {
+	for _, fieldErr := range e.errors {
+		if matchPath(fieldErr.Field, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// First returns the first error message reported for path, or an empty string.
+func (e *ValidationErrors) First(path string) string {
+	for _, fieldErr := range e.errors {
+		if matchPath(fieldErr.Field, path) {
+			return fieldErr.Message
+		}
+	}
+	return ""
+}
+
+// All returns every field error in the order the fields were declared.
+func (e *ValidationErrors) All() []*FieldError {
+	return append([]*FieldError(nil), e.errors...)
+}
+
+// Fields returns the paths of the fields that failed validation.
+func (e *ValidationErrors) Fields() []string {
+	seen := make(map[string]bool, len(e.errors))
+	fields := make([]string, 0, len(e.errors))
+	for _, fieldErr := range e.errors {
+		if !seen[fieldErr.Field] {
+			seen[fieldErr.Field] = true
+			fields = append(fields, fieldErr.Field)
+		}
+	}
+	return fields
+}
+
+// Flatten returns the messages keyed by full path, e.g. contacts[0].email with the "." separator
+// and IndexBracket. A field failing several rules maps to the list of its messages.
+func (e *ValidationErrors) Flatten(separator string, style IndexStyle) map[string]any {
+	errMsg := make(map[string]any, len(e.errors))
+	for _, fieldErr := range e.errors {
+		key := fieldErr.path.format(separator, style)
+		switch existing := errMsg[key].(type) {
+		case nil:
+			errMsg[key] = fieldErr.Message
+		case string:
+			errMsg[key] = []string{existing, fieldErr.Message}
+		case []string:
+			errMsg[key] = append(existing, fieldErr.Message)
+		}
+	}
+	return errMsg
+}
+
+// MarshalJSON encodes the errors using the map layout returned by earlier versions:
+// the message keyed by JSON key, nested maps for nested structs, a list for slice items
+// and "key.index" entries for slices of structs.
+// Validators created with WithFlatErrors encode the map returned by Flatten instead.
+func (e *ValidationErrors) MarshalJSON() ([]byte, error) {
+	if e.flat != nil {
+		return json.Marshal(e.Flatten(e.flat.separator, e.flat.style))
+	}
+	errMsg := make(map[string]any, len(e.errors))
+	for _, fieldErr := range e.errors {
+		setMapMessage(errMsg, fieldErr.path, fieldErr.Message)
+	}
+	return json.Marshal(errMsg)
+}
+
+// format writes the path with separator between keys and indexes written in style.
+func (p fieldPath) format(separator string, style IndexStyle) string {
+	var b strings.Builder
+	for i, seg := range p {
+		if seg.index >= 0 && style == IndexBracket {
+			b.WriteString("[" + strconv.Itoa(seg.index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(seg.key)
+	}
+	return b.String()
+}
+
+func matchPath(field, path string) bool {
+	return field == path || strings.HasPrefix(field, path+".")
+}
@@ -3,8 +3,9 @@ module github.com/SeyramWood/validata
 go 1.21.3
 
 require (
-	github.com/gabriel-vasile/mimetype v1.4.3 // indirect
-	github.com/go-sql-driver/mysql v1.7.1 // indirect
-	github.com/lib/pq v1.10.9 // indirect
-	golang.org/x/net v0.17.0 // indirect
+	github.com/gabriel-vasile/mimetype v1.4.3
+	github.com/go-sql-driver/mysql v1.7.1
+	github.com/lib/pq v1.10.9
 )
+
+require golang.org/x/net v0.17.0 // indirect
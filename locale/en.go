@@ -2,29 +2,54 @@ package locale
 
 // EN locale validation message.
 var EN = map[string]any{
-	"required":        "The %s field is required.",
-	"string":          "The %s must be a string.",
-	"alpha":           "The %s may only contain letters.",
-	"numeric":         "The %s must be a number.",
-	"alpha_numeric":   "The %s may only contain letters and numbers.",
-	"int":             "The %s must be an integer.",
-	"uint":            "The %s must be a positive integer.",
-	"float":           "The %s must be a float.",
-	"email":           "The %s must be a valid email address.",
-	"phone":           "The %s must be a valid phone number.",
-	"phone_with_code": "The %s must be a valid phone number with country code.",
-	"username":        "The %s must be a valid email address or phone number or phone number with country code.",
-	"match":           "The %s does not matched.",
-	"same":            "The %s and %s must match.",
-	"unique":          "The %s has already been taken.",
-	"bool":            "The %s field must be true.",
-	"file":            "The %s must be a file.",
-	"file_type":       "The %s must be a file of type: %s.",
-	"image":           "The %s must be an image.",
-	"image_type":      "The %s must be an image of type: %s.",
-	"mimes":           "The %s must be a file of type: %s.",
-	"gh_card":         "The %s must be a valid Ghana Card.",
-	"gh_gps":          "The %s must be a valid Ghana digital address.",
+	"required":             "The %s field is required.",
+	"required_if":          "The %s field is required when %s is %s.",
+	"required_unless":      "The %s field is required unless %s is in %s.",
+	"required_with":        "The %s field is required when %s is present.",
+	"required_with_all":    "The %s field is required when %s are present.",
+	"required_without":     "The %s field is required when %s is not present.",
+	"required_without_all": "The %s field is required when none of %s are present.",
+	"string":               "The %s must be a string.",
+	"alpha":                "The %s may only contain letters.",
+	"numeric":              "The %s must be a number.",
+	"alpha_numeric":        "The %s may only contain letters and numbers.",
+	"int":                  "The %s must be an integer.",
+	"uint":                 "The %s must be a positive integer.",
+	"float":                "The %s must be a float.",
+	"email":                "The %s must be a valid email address.",
+	"phone":                "The %s must be a valid phone number.",
+	"phone_with_code":      "The %s must be a valid phone number with country code.",
+	"username":             "The %s must be a valid email address or phone number or phone number with country code.",
+	"match":                "The %s does not matched.",
+	"same":                 "The %s and %s must match.",
+	"different":            "The %s and %s must be different.",
+	"eq_field":             "The %s must be equal to %s.",
+	"ne_field":             "The %s must not be equal to %s.",
+	"gt_field":             "The %s must be greater than %s.",
+	"lt_field":             "The %s must be less than %s.",
+	"confirmed":            "The %s confirmation does not match.",
+	"in":                   "The %s must be one of: %s.",
+	"not_in":               "The %s must not be one of: %s.",
+	"regex":                "The %s format is invalid.",
+	"unique":               "The %s has already been taken.",
+	"bool":                 "The %s field must be true.",
+	"file":                 "The %s must be a file.",
+	"file_type":            "The %s must be a file of type: %s.",
+	"image":                "The %s must be an image.",
+	"image_type":           "The %s must be an image of type: %s.",
+	"mimes":                "The %s must be a file of type: %s.",
+	"gh_card":              "The %s must be a valid Ghana Card.",
+	"gh_gps":               "The %s must be a valid Ghana digital address.",
+	"present":              "The %s field must be present.",
+	"filled":               "The %s field must have a value.",
+	"missing":              "The %s field must be missing.",
+	"date":                 "The %s is not a valid date.",
+	"date_format":          "The %s does not match the format %s.",
+	"before":               "The %s must be a date before %s.",
+	"before_or_equal":      "The %s must be a date before or equal to %s.",
+	"after":                "The %s must be a date after %s.",
+	"after_or_equal":       "The %s must be a date after or equal to %s.",
+	"invalid":              "The %s is invalid.",
 	"gt": map[string]string{
 		"numeric": "The %s must be greater than %s.",
 		"file":    "The %s must be greater than %s megabytes.",
@@ -2,29 +2,54 @@ package locale
 
 // FR locale validation message.
 var FR = map[string]any{
-	"required":        "Le champ %s est requis.",
-	"string":          "Le champ %s doit être une chaîne de caractères.",
-	"alpha":           "Le champ %s ne peut contenir que des lettres.",
-	"numeric":         "Le champ %s doit être un nombre.",
-	"alpha_numeric":   "Le champ %s ne peut contenir que des lettres et des chiffres.",
-	"int":             "Le champ %s doit être un entier.",
-	"uint":            "Le champ %s doit être un entier positif.",
-	"float":           "Le champ %s doit être un nombre décimal.",
-	"email":           "Le champ %s doit être une adresse email valide.",
-	"phone":           "Le champ %s doit être un numéro de téléphone valide.",
-	"phone_with_code": "Le champ %s doit être un numéro de téléphone valide avec le code du pays.",
-	"username":        "Le champ %s doit être une adresse email valide, un numéro de téléphone valide ou un numéro de téléphone avec le code du pays.",
-	"match":           "Le champ %s ne correspond pas.",
-	"same":            "Les champs %s et %s doivent correspondre.",
-	"unique":          "Le %s a déjà été pris.",
-	"bool":            "Le champ %s doit être vrai.",
-	"file":            "Le champ %s doit être un fichier.",
-	"file_type":       "Le champ %s doit être un fichier de type : %s.",
-	"image":           "Le champ %s doit être une image.",
-	"image_type":      "Le champ %s doit être une image de type : %s.",
-	"mimes":           "Le champ %s doit être un fichier de type : %s.",
-	"gh_card":         "Le champ %s doit être une carte d'identité ghanéenne valide.",
-	"gh_gps":          "Le champ %s doit être une adresse numérique ghanéenne valide.",
+	"required":             "Le champ %s est requis.",
+	"required_if":          "Le champ %s est requis quand %s vaut %s.",
+	"required_unless":      "Le champ %s est requis sauf si %s est dans %s.",
+	"required_with":        "Le champ %s est requis quand %s est présent.",
+	"required_with_all":    "Le champ %s est requis quand %s sont présents.",
+	"required_without":     "Le champ %s est requis quand %s n'est pas présent.",
+	"required_without_all": "Le champ %s est requis quand aucun de %s n'est présent.",
+	"string":               "Le champ %s doit être une chaîne de caractères.",
+	"alpha":                "Le champ %s ne peut contenir que des lettres.",
+	"numeric":              "Le champ %s doit être un nombre.",
+	"alpha_numeric":        "Le champ %s ne peut contenir que des lettres et des chiffres.",
+	"int":                  "Le champ %s doit être un entier.",
+	"uint":                 "Le champ %s doit être un entier positif.",
+	"float":                "Le champ %s doit être un nombre décimal.",
+	"email":                "Le champ %s doit être une adresse email valide.",
+	"phone":                "Le champ %s doit être un numéro de téléphone valide.",
+	"phone_with_code":      "Le champ %s doit être un numéro de téléphone valide avec le code du pays.",
+	"username":             "Le champ %s doit être une adresse email valide, un numéro de téléphone valide ou un numéro de téléphone avec le code du pays.",
+	"match":                "Le champ %s ne correspond pas.",
+	"same":                 "Les champs %s et %s doivent correspondre.",
+	"different":            "Les champs %s et %s doivent être différents.",
+	"eq_field":             "Le champ %s doit être égal à %s.",
+	"ne_field":             "Le champ %s ne doit pas être égal à %s.",
+	"gt_field":             "Le champ %s doit être supérieur à %s.",
+	"lt_field":             "Le champ %s doit être inférieur à %s.",
+	"confirmed":            "La confirmation du champ %s ne correspond pas.",
+	"in":                   "Le champ %s doit être l'une des valeurs suivantes : %s.",
+	"not_in":               "Le champ %s ne doit pas être l'une des valeurs suivantes : %s.",
+	"regex":                "Le format du champ %s n'est pas valide.",
+	"unique":               "Le %s a déjà été pris.",
+	"bool":                 "Le champ %s doit être vrai.",
+	"file":                 "Le champ %s doit être un fichier.",
+	"file_type":            "Le champ %s doit être un fichier de type : %s.",
+	"image":                "Le champ %s doit être une image.",
+	"image_type":           "Le champ %s doit être une image de type : %s.",
+	"mimes":                "Le champ %s doit être un fichier de type : %s.",
+	"gh_card":              "Le champ %s doit être une carte d'identité ghanéenne valide.",
+	"gh_gps":               "Le champ %s doit être une adresse numérique ghanéenne valide.",
+	"present":              "Le champ %s doit être présent.",
+	"filled":               "Le champ %s doit avoir une valeur.",
+	"missing":              "Le champ %s doit être absent.",
+	"date":                 "Le champ %s n'est pas une date valide.",
+	"date_format":          "Le champ %s ne correspond pas au format %s.",
+	"before":               "Le champ %s doit être une date antérieure à %s.",
+	"before_or_equal":      "Le champ %s doit être une date antérieure ou égale à %s.",
+	"after":                "Le champ %s doit être une date postérieure à %s.",
+	"after_or_equal":       "Le champ %s doit être une date postérieure ou égale à %s.",
+	"invalid":              "Le champ %s n'est pas valide.",
 	"gt": map[string]string{
 		"numeric": "Le champ %s doit être supérieur à %s.",
 		"file":    "Le champ %s doit être supérieur à %s mégaoctets.",
@@ -0,0 +1,85 @@
+package validata
+
+import "time"
+
+// Option configures a Validator created by New.
+type Option interface {
+	apply(v *Validator)
+}
+
+type optionFunc func(v *Validator)
+
+func (f optionFunc) apply(v *Validator) {
+	f(v)
+}
+
+// WithLocale sets the default locale of the error messages, e.g. LocaleFR.
+// A locale passed to Validate takes precedence.
+func WithLocale(locale string) Option {
+	return optionFunc(func(v *Validator) {
+		v.locale = locale
+	})
+}
+
+// WithDatabase sets the database configuration used by rules such as unique.
+func WithDatabase(config *Database) Option {
+	return optionFunc(func(v *Validator) {
+		v.dbConfig = config
+	})
+}
+
+// WithTagName sets the struct tag holding the validation rules, e.g. binding or rules. It defaults to validate.
+// Fields without that tag are not validated.
+func WithTagName(name string) Option {
+	return optionFunc(func(v *Validator) {
+		v.tagName = name
+	})
+}
+
+// WithKeyTagName sets the struct tag naming the fields in error paths, e.g. form. It defaults to json.
+// Fields without a name in that tag use their Go name.
+func WithKeyTagName(name string) Option {
+	return optionFunc(func(v *Validator) {
+		v.keyTagName = name
+	})
+}
+
+// WithStopOnFirstError stops the validation at the first field that fails.
+// Fields are then validated one after another instead of concurrently.
+func WithStopOnFirstError() Option {
+	return optionFunc(func(v *Validator) {
+		v.stopOnFirstError = true
+	})
+}
+
+// WithCollectAllErrors evaluates every rule of a field and reports all of its failures,
+// instead of stopping at the first failing rule. Fields with the bail rule still stop at the first failure.
+func WithCollectAllErrors() Option {
+	return optionFunc(func(v *Validator) {
+		v.collectAllErrors = true
+	})
+}
+
+// WithFlatErrors encodes the validation errors as a flat map keyed by full path, e.g. contacts.0.email,
+// instead of nested maps. Keys are joined with separator and indexes are written in style.
+func WithFlatErrors(separator string, style IndexStyle) Option {
+	return optionFunc(func(v *Validator) {
+		v.flatErrors = &flatFormat{separator: separator, style: style}
+	})
+}
+
+// WithItemLabel sets the format naming slice items in messages. It receives the field name and
+// the 1-based item number, and defaults to "%s (%d)", e.g. tags (2).
+func WithItemLabel(format string) Option {
+	return optionFunc(func(v *Validator) {
+		v.itemLabel = format
+	})
+}
+
+// WithClock sets the function returning the current time, used by date rules such as after:today.
+// It defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return optionFunc(func(v *Validator) {
+		v.clock = now
+	})
+}
@@ -0,0 +1,163 @@
+package validata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type filterMode int
+
+const (
+	filterPartial filterMode = iota
+	filterExcept
+	filterPresent
+)
+
+// pathFilter restricts a validation to some fields.
+// Paths are dotted JSON keys where a * segment matches any slice index or map key.
+type pathFilter struct {
+	mode    filterMode
+	paths   [][]string
+	present map[string]bool
+}
+
+func newPathFilter(mode filterMode, fields []string) *pathFilter {
+	filter := &pathFilter{mode: mode}
+	for _, field := range fields {
+		filter.paths = append(filter.paths, strings.Split(field, "."))
+	}
+	return filter
+}
+
+// check reports whether the rules of the field at path apply and whether the fields nested in it
+// may still be validated.
+func (f *pathFilter) check(path fieldPath) (validate, descend bool) {
+	if f == nil {
+		return true, true
+	}
+	switch f.mode {
+	case filterExcept:
+		for _, keys := range f.paths {
+			if len(keys) <= len(path) && matchKeys(keys, path[:len(keys)]) {
+				return false, false
+			}
+		}
+		return true, true
+	case filterPresent:
+		validate = f.present[strings.ToLower(path.String())]
+		return validate, validate
+	}
+	for _, keys := range f.paths {
+		if len(keys) <= len(path) && matchKeys(keys, path[:len(keys)]) {
+			return true, true
+		}
+		if len(keys) > len(path) && matchKeys(keys[:len(path)], path) {
+			descend = true
+		}
+	}
+	return false, descend
+}
+
+// filterErrors drops the errors reported outside of the filtered fields.
+func (f *pathFilter) filterErrors(errMsgs []*FieldError) []*FieldError {
+	if f == nil {
+		return errMsgs
+	}
+	kept := errMsgs[:0]
+	for _, fieldErr := range errMsgs {
+		if validate, _ := f.check(fieldErr.path); validate {
+			kept = append(kept, fieldErr)
+		}
+	}
+	return kept
+}
+
+func matchKeys(keys []string, path fieldPath) bool {
+	for i, key := range keys {
+		if key != "*" && key != path[i].key {
+			return false
+		}
+	}
+	return true
+}
+
+// presentPaths returns the lower-cased dotted paths of every key and array item present in a JSON body.
+func presentPaths(body []byte) (map[string]bool, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool)
+	var walk func(prefix string, data any)
+	walk = func(prefix string, data any) {
+		join := func(key string) string {
+			if prefix == "" {
+				return key
+			}
+			return prefix + "." + key
+		}
+		switch data := data.(type) {
+		case map[string]any:
+			for key, value := range data {
+				path := join(strings.ToLower(key))
+				present[path] = true
+				walk(path, value)
+			}
+		case []any:
+			for i, value := range data {
+				path := join(strconv.Itoa(i))
+				present[path] = true
+				walk(path, value)
+			}
+		}
+	}
+	walk("", data)
+	return present, nil
+}
+
+// ValidatePartial validates only the given fields of elem, e.g. for PATCH requests.
+// Fields are dotted JSON paths into nested structs and slices, such as address.city or contacts.*.email.
+// The rules of a field apply to everything nested in it.
+func (v *Validator) ValidatePartial(elem any, fields ...string) error {
+	instance := v.newValidation(context.Background(), nil)
+	instance.filter = newPathFilter(filterPartial, fields)
+	return instance.result(instance.validate(elem))
+}
+
+// ValidateExcept validates every field of elem but the given ones and the fields nested in them.
+// Fields are dotted JSON paths as in ValidatePartial.
+func (v *Validator) ValidateExcept(elem any, fields ...string) error {
+	instance := v.newValidation(context.Background(), nil)
+	instance.filter = newPathFilter(filterExcept, fields)
+	return instance.result(instance.validate(elem))
+}
+
+// ValidatePresent decodes the JSON body into elem and validates only the keys present in body.
+// It returns the decoding error, if any, before validating.
+func (v *Validator) ValidatePresent(body []byte, elem any) error {
+	present, err := presentPaths(body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, elem); err != nil {
+		return err
+	}
+	instance := v.newValidation(context.Background(), nil)
+	instance.present = present
+	instance.filter = &pathFilter{mode: filterPresent, present: present}
+	return instance.result(instance.validate(elem))
+}
+
+// ValidatePartialRequest behaves like ValidateRequest but validates only the keys present
+// in the JSON request body, which suits PATCH handlers.
+func (v *Validator) ValidatePartialRequest(elem any, next http.Handler) http.Handler {
+	elemType := reflect.TypeOf(elem)
+	if elemType == nil || elemType.Kind() != reflect.Pointer {
+		panic("validate: a pointer is expected as an argument")
+	}
+	return v.validateRequest(func() any { return reflect.New(elemType.Elem()).Interface() }, nil, true, next)
+}
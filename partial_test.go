@@ -0,0 +1,89 @@
+package validata
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testProfile struct {
+	Name     string            `json:"name" validate:"required|string"`
+	Email    string            `json:"email" validate:"required|email"`
+	Contact  *TestDeepStruct   `json:"contact" validate:"required"`
+	Contacts []*TestDeepStruct `json:"contacts" validate:"required|min:1"`
+}
+
+func TestValidatePartial(t *testing.T) {
+	profile := &testProfile{
+		Email:   "invalid",
+		Contact: &TestDeepStruct{Email: "invalid"},
+		Contacts: []*TestDeepStruct{
+			{Name: "Wood", Email: "invalid"},
+			{Email: "contact@mail.com"},
+		},
+	}
+	tests := []struct {
+		name     string
+		validate func() error
+		fields   []string
+	}{
+		{"partial", func() error { return New().ValidatePartial(profile, "email") }, []string{"email"}},
+		{"partial nested", func() error { return New().ValidatePartial(profile, "contact.email") }, []string{"contact.email"}},
+		{"partial struct", func() error { return New().ValidatePartial(profile, "contact") }, []string{"contact.name", "contact.email"}},
+		{"partial slice", func() error { return New().ValidatePartial(profile, "contacts.*.name") }, []string{"contacts.1.name"}},
+		{"partial index", func() error { return New().ValidatePartial(profile, "contacts.0") }, []string{"contacts.0.email"}},
+		{"except", func() error { return New().ValidateExcept(profile, "name", "contact", "contacts.*.email") }, []string{"email", "contacts.1.name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs *ValidationErrors
+			if !errors.As(tt.validate(), &errs) {
+				t.Fatalf("expected *ValidationErrors")
+			}
+			if got := strings.Join(errs.Fields(), ","); got != strings.Join(tt.fields, ",") {
+				t.Errorf("got fields %s, want %s", got, strings.Join(tt.fields, ","))
+			}
+		})
+	}
+	if err := New().ValidatePartial(profile, "contacts"); err == nil || !err.(*ValidationErrors).Has("contacts.0.email") {
+		t.Errorf("expected the contacts items to be validated, got %v", err)
+	}
+}
+
+func TestValidatePresent(t *testing.T) {
+	var profile testProfile
+	err := New().ValidatePresent([]byte(`{"email":"invalid","contacts":[{"name":"Wood"}]}`), &profile)
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if got := strings.Join(errs.Fields(), ","); got != "email" {
+		t.Errorf("expected only the present keys to be validated, got %s", got)
+	}
+	if err := New().ValidatePresent([]byte(`{"name":"Wood"}`), &profile); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := New().ValidatePresent([]byte(`{`), &profile); err == nil || errors.As(err, &errs) {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+}
+
+func TestValidatePartialRequest(t *testing.T) {
+	handler := New().ValidatePartialRequest(&testProfile{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"Name":"Wood"}`)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"contact":{"email":"invalid"}}`)))
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `{"contact":{"email":"The email must be a valid email address."}}`) {
+		t.Errorf("expected 422 for contact.email only, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,208 @@
+package validata
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structPlan holds everything needed to validate a struct type, resolved once per type.
+type structPlan struct {
+	fields []*fieldPlan          // validated fields
+	byName map[string]*fieldPlan // every field, for lookups by key
+}
+
+type fieldPlan struct {
+	index []int
+	name  string
+	label string
+	rules []*parsedRule
+}
+
+// value returns the field in the struct parent, or an invalid value when an embedded pointer
+// holding the field is nil.
+func (f *fieldPlan) value(parent reflect.Value) reflect.Value {
+	value, err := parent.FieldByIndexErr(f.index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return value
+}
+
+type planKey struct {
+	typ        reflect.Type
+	tagName    string
+	keyTagName string
+}
+
+var (
+	plans     sync.Map // planKey -> *structPlan
+	ruleCache sync.Map // validation tag -> []*parsedRule
+)
+
+// Compile builds and caches the validation plan of T and of the structs nested in it,
+// so the first Validate call does not pay for it. Options select the tag names, as in New.
+// It returns a *TagError when the validation tag of a field of T is malformed.
+func Compile[T any](opts ...Option) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	return New(opts...).compile(typ, make(map[reflect.Type]bool))
+}
+
+func (v *Validator) compile(typ reflect.Type, seen map[reflect.Type]bool) error {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || scalarStruct(typ) || seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+	plan, err := compilePlan(typ, v.tagName, v.keyTagName)
+	if err != nil {
+		return err
+	}
+	plans.Store(v.planKey(typ), plan)
+	for _, field := range plan.fields {
+		if err := v.compile(typ.FieldByIndex(field.index).Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPlan returns the cached plan of typ, compiling it on first use.
+func (v *Validator) loadPlan(typ reflect.Type) (*structPlan, error) {
+	key := v.planKey(typ)
+	if plan, ok := plans.Load(key); ok {
+		return plan.(*structPlan), nil
+	}
+	plan, err := compilePlan(typ, v.tagName, v.keyTagName)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := plans.LoadOrStore(key, plan)
+	return actual.(*structPlan), nil
+}
+
+func (v *Validator) planKey(typ reflect.Type) planKey {
+	return planKey{typ: typ, tagName: v.tagName, keyTagName: v.keyTagName}
+}
+
+// compilePlan resolves the fields of typ. Unexported fields, other than embedded structs, and fields whose
+// key tag is "-" are skipped.
+// A field is keyed by the name in its key tag, or by its Go name when the tag has none.
+// Every other field can be referenced by the rules of its siblings, but only fields with a rules tag
+// other than "-" are validated.
+// The fields of embedded structs without a key name are promoted as in Go: a field of the outer
+// struct hides a promoted field with the same name.
+func compilePlan(typ reflect.Type, tagName, keyTagName string) (*structPlan, error) {
+	type candidate struct {
+		field     *fieldPlan
+		validated bool
+	}
+	var candidates []candidate
+	depths := make(map[string]int)
+	var collect func(typ reflect.Type, index []int, seen map[reflect.Type]bool) error
+	collect = func(typ reflect.Type, index []int, seen map[reflect.Type]bool) error {
+		seen[typ] = true
+		defer delete(seen, typ)
+		for i := 0; i < typ.NumField(); i++ {
+			structField := typ.Field(i)
+			rules, hasRules := structField.Tag.Lookup(tagName)
+			name, _, _ := strings.Cut(structField.Tag.Get(keyTagName), ",")
+			if name == "-" {
+				continue
+			}
+			fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+			// The exported fields of an embedded struct are promoted even when its type is unexported.
+			if embedded, ok := embeddedStruct(structField); ok && name == "" && rules != "-" {
+				if !seen[embedded] {
+					if err := collect(embedded, fieldIndex, seen); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !structField.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = structField.Name
+			}
+			validated := hasRules && rules != "-"
+			var parsed []*parsedRule
+			if validated {
+				var err error
+				if parsed, err = compileRules(rules, typ.Name()+"."+structField.Name); err != nil {
+					return err
+				}
+			}
+			if depth, ok := depths[name]; !ok || len(fieldIndex) < depth {
+				depths[name] = len(fieldIndex)
+			}
+			candidates = append(candidates, candidate{field: &fieldPlan{
+				index: fieldIndex,
+				name:  name,
+				label: formatFieldName(name),
+				rules: parsed,
+			}, validated: validated})
+		}
+		return nil
+	}
+	if err := collect(typ, nil, make(map[reflect.Type]bool)); err != nil {
+		return nil, err
+	}
+	plan := &structPlan{
+		fields: make([]*fieldPlan, 0, len(candidates)),
+		byName: make(map[string]*fieldPlan, len(candidates)),
+	}
+	for _, c := range candidates {
+		field := c.field
+		if _, ok := plan.byName[field.name]; ok || len(field.index) != depths[field.name] {
+			continue
+		}
+		if c.validated {
+			plan.fields = append(plan.fields, field)
+		}
+		plan.byName[field.name] = field
+	}
+	return plan, nil
+}
+
+// embeddedStruct returns the struct type of an embedded field, dereferencing pointers.
+func embeddedStruct(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ, typ.Kind() == reflect.Struct && !scalarStruct(typ)
+}
+
+// compileRules returns the parsed rules of a validation tag, parsing each distinct tag once.
+// field names the struct field or map key holding the tag in parse errors.
+func compileRules(tag, field string) ([]*parsedRule, error) {
+	if rules, ok := ruleCache.Load(tag); ok {
+		return rules.([]*parsedRule), nil
+	}
+	parsed, err := parseTag(tag)
+	if err != nil {
+		err.Field = field
+		return nil, err
+	}
+	rules, _ := ruleCache.LoadOrStore(tag, parsed)
+	return rules.([]*parsedRule), nil
+}
+
+// resetPlans drops the cached plans so that they pick up newly registered rules.
+func resetPlans() {
+	plans.Range(func(key, _ any) bool {
+		plans.Delete(key)
+		return true
+	})
+	ruleCache.Range(func(key, _ any) bool {
+		ruleCache.Delete(key)
+		return true
+	})
+}
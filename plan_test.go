@@ -0,0 +1,116 @@
+package validata
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func benchRequest() *TestStruct {
+	return &TestStruct{
+		Name:        "Wood",
+		Description: "Nihil quis dolorum temporibus vitae delectus dolorem.",
+		Phone:       "0241234567",
+		Username:    "admin@foodivoire.com",
+		Terms:       true,
+		Items:       []string{"quis Ut"},
+		Contact:     &TestDeepStruct{Name: "Wood White", Phone: "+233265518694", Email: "contact@mail.com"},
+		Contacts:    []*TestDeepStruct{{Name: "Wood White", Phone: "+233265518694", Email: "contact@mail.com"}},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if err := Compile[TestStruct](); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator := New()
+	for _, typ := range []any{TestStruct{}, TestDeepStruct{}} {
+		if _, ok := plans.Load(validator.planKey(reflect.TypeOf(typ))); !ok {
+			t.Errorf("expected a cached plan for %T", typ)
+		}
+	}
+	type untagged struct {
+		Name string
+	}
+	if err := Compile[untagged](); err != nil {
+		t.Errorf("expected fields without tags to be skipped, got %v", err)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	validator := New()
+	if err := Compile[TestStruct](); err != nil {
+		b.Fatal(err)
+	}
+	request := benchRequest()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(request)
+	}
+}
+
+// BenchmarkValidateUncached re-reads the tags and re-parses the rules on every call,
+// as Validate did before plans were cached. Compared with BenchmarkValidate, it shows the cost of the plans
+// only: the rule helpers keep their precompiled patterns, so it understates the former path, which also
+// compiled a pattern on every rule check. BenchmarkPatternCompile measures that part.
+func BenchmarkValidateUncached(b *testing.B) {
+	validator := New()
+	request := benchRequest()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resetPlans()
+		validator.Validate(request)
+	}
+}
+
+func TestUntaggedFieldReferences(t *testing.T) {
+	type request struct {
+		Min      float64 `json:"min"`
+		Price    float64 `json:"price" validate:"gt:min"`
+		Password string  `json:"password" validate:"same:password_repeat"`
+		Repeat   string  `json:"password_repeat"`
+		Hidden   string  `json:"hidden" validate:"-"`
+		Note     string  `json:"note" validate:"required_with:hidden"`
+	}
+	expectErrors(t, New().Validate(&request{Min: 1, Price: 5, Password: "secret", Repeat: "secret"}), nil)
+	expectErrors(t, New().Validate(&request{Min: 10, Price: 5, Password: "secret", Repeat: "other", Hidden: "x"}), map[string]string{
+		"price":    "The price must be greater than 10.",
+		"password": "The password and password_repeat must match.",
+		"note":     "The note field is required when hidden is present.",
+	})
+}
+
+// BenchmarkPatternCompile compares a rule check using a precompiled pattern with the same check compiling
+// its pattern first, as the rule helpers did before plans were cached.
+func BenchmarkPatternCompile(b *testing.B) {
+	b.Run("precompiled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			phoneRgx.MatchString("0241234567")
+		}
+	})
+	b.Run("per check", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rgx, _ := regexp.Compile(phoneRgx.String())
+			rgx.MatchString("0241234567")
+		}
+	})
+}
+
+func TestFormatFieldName(t *testing.T) {
+	for key, label := range map[string]string{
+		"price":     "price",
+		"minPrice":  "min price",
+		"min_price": "min price",
+		"min-price": "min price",
+		"min__sale": "min sale",
+		"_internal": "internal",
+	} {
+		if got := formatFieldName(key); got != label {
+			t.Errorf("%s: got %q, want %q", key, got, label)
+		}
+	}
+}
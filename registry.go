@@ -0,0 +1,725 @@
+package validata
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RuleFunc reports whether value satisfies a rule.
+// params holds the rule parameters, e.g. ["1", "5"] for between:1,5.
+type RuleFunc func(value reflect.Value, params []string, ctx *RuleContext) bool
+
+// RuleOptions describes a rule registered with RegisterRule.
+type RuleOptions struct {
+	// Kinds lists the kinds the rule supports. Values of other kinds skip the rule.
+	// An empty list applies the rule to every kind.
+	Kinds []reflect.Kind
+	// MessageKey is the key of the rule message in the locale maps. It defaults to the rule name.
+	// The message may be a string or a map keyed by numeric, string, slice and file.
+	MessageKey string
+	// Messages holds the rule message per locale, e.g. {"en": "The %s must be even."}.
+	// It takes precedence over the locale maps.
+	Messages map[string]string
+	// Params is the number of parameters the rule requires. Tags giving fewer fail to parse.
+	Params int
+}
+
+// RuleContext gives a rule access to the data surrounding the validated value.
+type RuleContext struct {
+	validation *validation
+	parent     reflect.Value
+	path       fieldPath
+	present    bool
+}
+
+// Field returns the full path of the validated field, e.g. contacts.0.email.
+func (c *RuleContext) Field() string {
+	return c.path.String()
+}
+
+// Parent returns the struct or map holding the validated field.
+func (c *RuleContext) Parent() reflect.Value {
+	return c.parent
+}
+
+// Root returns the value passed to Validate.
+func (c *RuleContext) Root() reflect.Value {
+	return c.validation.root
+}
+
+// Context returns the context of the validation. Rules doing I/O should honour it.
+func (c *RuleContext) Context() context.Context {
+	return c.validation.ctx
+}
+
+// Locale returns the locale the messages are rendered in.
+func (c *RuleContext) Locale() string {
+	return c.validation.getLocale()
+}
+
+type ruleDef struct {
+	fn       RuleFunc
+	kinds    []reflect.Kind
+	key      string
+	implicit bool
+	message  messageFunc
+	params   int
+}
+
+// messageFunc returns the locale message key and arguments of a failed rule.
+type messageFunc func(value reflect.Value, params []string, ctx *RuleContext) (key string, args []string)
+
+func (d *ruleDef) accepts(value reflect.Value) bool {
+	if len(d.kinds) == 0 {
+		return true
+	}
+	for _, kind := range d.kinds {
+		if value.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var registry = struct {
+	sync.RWMutex
+	rules    map[string]*ruleDef
+	messages map[string]map[string]string
+	patterns map[string]*regexp.Regexp
+}{
+	messages: make(map[string]map[string]string),
+	patterns: make(map[string]*regexp.Regexp),
+}
+
+func init() {
+	registry.rules = builtinRules()
+}
+
+// RegisterRule registers a rule usable in validation tags, slices and map rules.
+// Registering an existing name replaces the rule, built-in rules included.
+// Rules are meant to be registered once at start-up, before validating.
+func RegisterRule(name string, fn RuleFunc, opts RuleOptions) {
+	if name == "" || fn == nil {
+		panic("validata: a rule name and function are required")
+	}
+	key := opts.MessageKey
+	if key == "" {
+		key = name
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	defer resetPlans()
+	registry.rules[name] = &ruleDef{
+		fn:     fn,
+		kinds:  opts.Kinds,
+		key:    key,
+		params: opts.Params,
+	}
+	for loc, msg := range opts.Messages {
+		setMessage(loc, key, msg)
+	}
+}
+
+// RegisterMessage registers the message of key in locale, e.g. RegisterMessage("en", "end_after_start", "The %s must be after %s.").
+// It takes precedence over the locale maps. Messages reported by StructLevel.ReportError are looked up by rule name,
+// so it lets struct-level validation localize its messages without registering a rule.
+func RegisterMessage(locale, key, msg string) {
+	if key == "" {
+		panic("validata: a message key is required")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	setMessage(locale, key, msg)
+}
+
+// setMessage stores msg under key in locale. The registry must be locked.
+func setMessage(locale, key, msg string) {
+	locale = strings.ToLower(locale)
+	if registry.messages[locale] == nil {
+		registry.messages[locale] = make(map[string]string)
+	}
+	registry.messages[locale][key] = msg
+}
+
+// RegisterPattern registers a regular expression usable by name in regex rules, e.g. regex:slug.
+// It panics if expr does not compile. Registering an existing name replaces the pattern.
+// Patterns are meant to be registered once at start-up, before the tags using them are parsed.
+func RegisterPattern(name, expr string) {
+	if name == "" {
+		panic("validata: a pattern name is required")
+	}
+	rgx := regexp.MustCompile(expr)
+	registry.Lock()
+	defer registry.Unlock()
+	defer resetPlans()
+	registry.patterns[name] = rgx
+}
+
+// lookupPattern returns the pattern registered as name.
+func lookupPattern(name string) (*regexp.Regexp, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	rgx, ok := registry.patterns[name]
+	return rgx, ok
+}
+
+func lookupRule(name string) *ruleDef {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.rules[name]
+}
+
+func registeredMessage(locale, key string) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	msg, ok := registry.messages[locale][key]
+	return msg, ok
+}
+
+var (
+	stringKinds  = []reflect.Kind{reflect.String}
+	intKinds     = []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr}
+	floatKinds   = []reflect.Kind{reflect.Float32, reflect.Float64}
+	numericKinds = append(append([]reflect.Kind{}, intKinds...), floatKinds...)
+	scalarKinds  = append(append([]reflect.Kind{}, stringKinds...), numericKinds...)
+	sizeKinds    = append(append([]reflect.Kind{}, scalarKinds...), reflect.Slice, reflect.Array, reflect.Map)
+	fileKinds    = []reflect.Kind{reflect.Pointer, reflect.Interface}
+
+	comparableKinds = append(append([]reflect.Kind{}, sizeKinds...), fileKinds...)
+	dateKinds       = []reflect.Kind{reflect.String, reflect.Struct}
+)
+
+func builtinRules() map[string]*ruleDef {
+	return map[string]*ruleDef{
+		"required": {
+			fn:       func(value reflect.Value, _ []string, ctx *RuleContext) bool { return ctx.present || !isEmpty(value) },
+			implicit: true,
+			message: func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+				if value.Kind() == reflect.Bool {
+					return "bool", nil
+				}
+				return "required", nil
+			},
+		},
+		"required_if": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return len(params) > 1 && equalsAny(ctx.Lookup(params[0]), params[1:])
+			}),
+			implicit: true,
+			message:  conditionMessage("required_if"),
+			params:   2,
+		},
+		"required_unless": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return len(params) > 1 && !equalsAny(ctx.Lookup(params[0]), params[1:])
+			}),
+			implicit: true,
+			message:  conditionMessage("required_unless"),
+			params:   2,
+		},
+		"required_with": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return ctx.countFilled(params) > 0
+			}),
+			implicit: true,
+			message:  fieldsMessage("required_with"),
+			params:   1,
+		},
+		"required_with_all": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return len(params) > 0 && ctx.countFilled(params) == len(params)
+			}),
+			implicit: true,
+			message:  fieldsMessage("required_with_all"),
+			params:   1,
+		},
+		"required_without": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return ctx.countFilled(params) < len(params)
+			}),
+			implicit: true,
+			message:  fieldsMessage("required_without"),
+			params:   1,
+		},
+		"required_without_all": {
+			fn: requiredWhen(func(params []string, ctx *RuleContext) bool {
+				return len(params) > 0 && ctx.countFilled(params) == 0
+			}),
+			implicit: true,
+			message:  fieldsMessage("required_without_all"),
+			params:   1,
+		},
+		"present": {
+			fn: func(value reflect.Value, _ []string, ctx *RuleContext) bool {
+				return ctx.present || ctx.validation.isPresent(value, ctx.path)
+			},
+			implicit: true,
+		},
+		"filled": {
+			fn: func(value reflect.Value, _ []string, ctx *RuleContext) bool {
+				return ctx.present || !isEmpty(value) || !ctx.validation.isPresent(value, ctx.path)
+			},
+			implicit: true,
+		},
+		"missing": {
+			fn: func(value reflect.Value, _ []string, ctx *RuleContext) bool {
+				return !ctx.present && !ctx.validation.isPresent(value, ctx.path)
+			},
+			implicit: true,
+		},
+		"string":          {fn: check(isNotString), kinds: stringKinds},
+		"ascii":           {fn: check(isNotASCII), kinds: stringKinds, key: "string"},
+		"alpha":           {fn: check(isNotAlpha), kinds: stringKinds},
+		"numeric":         {fn: check(isNotNumeric), kinds: stringKinds},
+		"alpha_numeric":   {fn: check(isNotAlphanumeric), kinds: stringKinds},
+		"email":           {fn: check(isNotEmail), kinds: stringKinds},
+		"phone":           {fn: check(isNotPhone), kinds: stringKinds},
+		"phone_with_code": {fn: check(isNotPhoneWithCode), kinds: stringKinds},
+		"username":        {fn: check(isNotUsername), kinds: stringKinds},
+		"gh_card":         {fn: check(isNotGHCard), kinds: stringKinds},
+		"gh_gps":          {fn: check(isNotGHGPS), kinds: stringKinds},
+		"int":             {fn: check(isNotInt), kinds: intKinds},
+		"uint":            {fn: check(isNotUint), kinds: intKinds},
+		"float":           {fn: check(isNotFloat), kinds: floatKinds},
+		"min":             {fn: checkParam(isNotMin), kinds: sizeKinds, params: 1},
+		"max":             {fn: checkParam(isNotMax), kinds: sizeKinds, params: 1},
+		"equal":           {fn: checkParam(isNotEqual), kinds: sizeKinds, params: 1},
+		"from":            {fn: checkRange(isNotFrom), kinds: sizeKinds, params: 2},
+		"between":         {fn: checkRange(isNotBetween), kinds: sizeKinds, params: 2},
+		"gt":              {fn: compareRule(func(c int) bool { return c > 0 }), kinds: comparableKinds, message: compareMessage("gt"), params: 1},
+		"gte":             {fn: compareRule(func(c int) bool { return c >= 0 }), kinds: comparableKinds, message: compareMessage("gte"), params: 1},
+		"lt":              {fn: compareRule(func(c int) bool { return c < 0 }), kinds: comparableKinds, message: compareMessage("lt"), params: 1},
+		"lte":             {fn: compareRule(func(c int) bool { return c <= 0 }), kinds: comparableKinds, message: compareMessage("lte"), params: 1},
+		"date": {
+			fn: func(value reflect.Value, _ []string, ctx *RuleContext) bool {
+				_, ok := dateOf(value, ctx.validation.validator.clock().Location())
+				return ok
+			},
+			kinds: dateKinds,
+		},
+		"date_format": {
+			fn: func(value reflect.Value, params []string, _ *RuleContext) bool {
+				return !isNotDateFormat(value, dateLayout(params))
+			},
+			kinds: stringKinds,
+			message: func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+				return "date_format", []string{dateLayout(params)}
+			},
+			params: 1,
+		},
+		"before":          {fn: compareDate(func(c int) bool { return c < 0 }), kinds: dateKinds, message: dateMessage("before"), params: 1},
+		"before_or_equal": {fn: compareDate(func(c int) bool { return c <= 0 }), kinds: dateKinds, message: dateMessage("before_or_equal"), params: 1},
+		"after":           {fn: compareDate(func(c int) bool { return c > 0 }), kinds: dateKinds, message: dateMessage("after"), params: 1},
+		"after_or_equal":  {fn: compareDate(func(c int) bool { return c >= 0 }), kinds: dateKinds, message: dateMessage("after_or_equal"), params: 1},
+		"size": {
+			fn: func(value reflect.Value, params []string, _ *RuleContext) bool {
+				if fh, ok := fileHeader(value); ok {
+					return !isNotFileSize(fh, param(params, 0))
+				}
+				return !isNotSize(value, param(params, 0))
+			},
+			kinds: append(append([]reflect.Kind{}, sizeKinds...), fileKinds...),
+			message: func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+				if _, ok := fileHeader(value); ok {
+					size, unit := parseFileSize(param(params, 0))
+					return "size.file_" + unit, []string{size}
+				}
+				return "size", params
+			},
+			params: 1,
+		},
+		"same": {
+			fn: func(value reflect.Value, params []string, ctx *RuleContext) bool {
+				return !isNotSame(value, ctx.Lookup(param(params, 0)))
+			},
+			message: sameMessage,
+			params:  1,
+		},
+		"match": {
+			fn: func(value reflect.Value, params []string, ctx *RuleContext) bool {
+				return !isNotSame(value, ctx.Lookup(param(params, 0)))
+			},
+			params: 1,
+		},
+		"in":        {fn: inRule(false, false), kinds: scalarKinds, message: listMessage("in"), params: 1},
+		"not_in":    {fn: inRule(true, false), kinds: scalarKinds, message: listMessage("not_in"), params: 1},
+		"in_ci":     {fn: inRule(false, true), kinds: scalarKinds, key: "in", message: listMessage("in"), params: 1},
+		"not_in_ci": {fn: inRule(true, true), kinds: scalarKinds, key: "not_in", message: listMessage("not_in"), params: 1},
+		"enum": {
+			fn: func(value reflect.Value, _ []string, _ *RuleContext) bool {
+				values, ok := enumValues(value)
+				if !ok {
+					return false
+				}
+				for _, v := range values {
+					if c, ok := compareValues(value, reflect.ValueOf(v)); ok && c == 0 {
+						return true
+					}
+				}
+				return false
+			},
+			message: func(value reflect.Value, _ []string, _ *RuleContext) (string, []string) {
+				values, ok := enumValues(value)
+				if !ok {
+					return "invalid", nil
+				}
+				names := make([]string, 0, len(values))
+				for _, v := range values {
+					names = append(names, fmt.Sprint(v))
+				}
+				return "in", []string{strings.Join(names, ", ")}
+			},
+		},
+		"regex": {
+			// The parser binds each regex rule to its compiled pattern, see tagParser.pattern.
+			fn:     func(reflect.Value, []string, *RuleContext) bool { return true },
+			kinds:  stringKinds,
+			params: 1,
+		},
+		"different": {fn: compareField(func(c int, ok bool) bool { return !ok || c != 0 }), message: fieldMessage("different"), params: 1},
+		"eq_field":  {fn: compareField(func(c int, ok bool) bool { return ok && c == 0 }), message: fieldMessage("eq_field"), params: 1},
+		"ne_field":  {fn: compareField(func(c int, ok bool) bool { return !ok || c != 0 }), message: fieldMessage("ne_field"), params: 1},
+		"gt_field":  {fn: compareField(func(c int, ok bool) bool { return ok && c > 0 }), message: fieldMessage("gt_field"), params: 1},
+		"lt_field":  {fn: compareField(func(c int, ok bool) bool { return ok && c < 0 }), message: fieldMessage("lt_field"), params: 1},
+		"confirmed": {
+			fn: func(value reflect.Value, params []string, ctx *RuleContext) bool {
+				c, ok := compareValues(value, ctx.Lookup(confirmation(params, ctx)))
+				return ok && c == 0
+			},
+		},
+		"unique": {
+			fn: func(value reflect.Value, params []string, ctx *RuleContext) bool {
+				if tc := strings.SplitN(param(params, 0), ".", 2); len(tc) == 2 {
+					return !isNotUnique(ctx.Context(), ctx.validation.validator.dbConfig, value.String(), tc[1], tc[0])
+				}
+				return true
+			},
+			kinds:  stringKinds,
+			params: 1,
+		},
+		"image": {
+			fn: checkFile(func(value reflect.Value, params []string) bool {
+				if len(params) == 0 {
+					return isNotMimes(value, "jpg,jpeg,png,webp")
+				}
+				return isNotMimes(value, strings.Join(params, ","))
+			}),
+			kinds:   fileKinds,
+			message: fileTypeMessage("image", "image_type"),
+		},
+		"file": {
+			fn: checkFile(func(value reflect.Value, params []string) bool {
+				if len(params) == 0 {
+					return isNotFile(value)
+				}
+				return isNotMimes(value, strings.Join(params, ","))
+			}),
+			kinds:   fileKinds,
+			message: fileTypeMessage("file", "file_type"),
+		},
+		"mimes": {
+			fn: checkFile(func(value reflect.Value, params []string) bool {
+				return isNotMimes(value, strings.Join(params, ","))
+			}),
+			kinds:   fileKinds,
+			message: fileTypeMessage("mimes", "mimes"),
+			params:  1,
+		},
+	}
+}
+
+// compareRule returns a rule comparing the size of the value with the size given by its parameter,
+// a number or a field reference. The rule passes when the parameter refers to an empty field.
+func compareRule(ok func(c int) bool) RuleFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) bool {
+		size, valid := sizeOf(value)
+		if !valid {
+			return true
+		}
+		other, resolved := ctx.reference(param(params, 0))
+		if !resolved {
+			return true
+		}
+		return ok(cmp.Compare(size, other))
+	}
+}
+
+func compareMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) (string, []string) {
+		other, _ := ctx.reference(param(params, 0))
+		return key, []string{strconv.FormatFloat(other, 'f', -1, 64)}
+	}
+}
+
+// reference returns the size given by a rule parameter: the parameter itself when it is a number,
+// otherwise the size of the field it references.
+func (c *RuleContext) reference(param string) (float64, bool) {
+	if n, err := strconv.ParseFloat(param, 64); err == nil {
+		return n, true
+	}
+	other := indirect(c.Lookup(param))
+	if isNil(other) {
+		return 0, false
+	}
+	return sizeOf(other)
+}
+
+// sizeOf returns the size compared by gt, gte, lt and lte: the number itself, the length of a string,
+// slice or map, or the size of a file in megabytes.
+func sizeOf(value reflect.Value) (float64, bool) {
+	if fh, ok := fileHeader(value); ok {
+		return float64(fh.Size) / megabyte, true
+	}
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// compareField returns a rule comparing the value with the field referenced by its parameter.
+func compareField(ok func(c int, comparable bool) bool) RuleFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) bool {
+		return ok(compareValues(value, ctx.Lookup(param(params, 0))))
+	}
+}
+
+// fieldMessage renders the field named by the parameter of a rule.
+func fieldMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+		return key, []string{referenceName(param(params, 0))}
+	}
+}
+
+// sameMessage names the field of a same rule: the key of a sibling as given, e.g. pin_repeat,
+// and the formatted name of a nested, parent or root field, e.g. password for ../password.
+func sameMessage(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+	ref := param(params, 0)
+	if !strings.HasPrefix(ref, "$.") && !strings.HasPrefix(ref, "../") && !strings.Contains(ref, ".") {
+		return "same", []string{ref}
+	}
+	return "same", []string{referenceName(ref)}
+}
+
+// referenceName formats the name of the field referenced by ref, e.g. budget min for $.budget.min.
+func referenceName(ref string) string {
+	ref = strings.TrimPrefix(ref, "$.")
+	for strings.HasPrefix(ref, "../") {
+		ref = ref[3:]
+	}
+	return formatFieldName(strings.ReplaceAll(ref, ".", "_"))
+}
+
+// confirmation returns the field confirming the validated field: the parameter of the confirmed rule,
+// or the key of the field followed by _confirmation, e.g. password_confirmation.
+func confirmation(params []string, ctx *RuleContext) string {
+	if len(params) > 0 {
+		return params[0]
+	}
+	return ctx.path.jsonKey() + "_confirmation"
+}
+
+// requiredWhen returns a rule requiring the value when condition holds.
+func requiredWhen(condition func(params []string, ctx *RuleContext) bool) RuleFunc {
+	return func(value reflect.Value, params []string, ctx *RuleContext) bool {
+		return ctx.present || !isEmpty(value) || !condition(params, ctx)
+	}
+}
+
+// conditionMessage renders the field and the values of a condition such as status,active,pending.
+func conditionMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+		if len(params) == 0 {
+			return key, nil
+		}
+		return key, []string{referenceName(params[0]), strings.Join(params[1:], ", ")}
+	}
+}
+
+// fieldsMessage renders the fields of a condition such as email,phone.
+func fieldsMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+		names := make([]string, 0, len(params))
+		for _, field := range params {
+			names = append(names, referenceName(field))
+		}
+		return key, []string{strings.Join(names, " / ")}
+	}
+}
+
+// Lookup returns the field referenced by ref, or an invalid value when there is none.
+// ref names a field of the struct or map holding the validated field, such as password or address.city.
+// It may refer to the parent struct with ../password, and to the root with $.budget.min.
+func (c *RuleContext) Lookup(ref string) reflect.Value {
+	return c.validation.reference(ref, c.path)
+}
+
+// countFilled returns how many of the referenced fields hold a value.
+func (c *RuleContext) countFilled(fields []string) int {
+	count := 0
+	for _, field := range fields {
+		if hasValue(c.Lookup(field)) {
+			count++
+		}
+	}
+	return count
+}
+
+// hasValue reports whether value is not empty. A non-nil pointer has a value, even when pointing to a zero value.
+func hasValue(value reflect.Value) bool {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Pointer {
+		return !value.IsNil()
+	}
+	return !isEmpty(value)
+}
+
+// Enum is implemented by enumeration types to list their allowed values, which the enum rule validates against.
+// The enum rule fails for values whose type does not implement Enum.
+//
+//	type Status string
+//
+//	func (Status) Enum() []any { return []any{StatusDraft, StatusPublished} }
+type Enum interface {
+	Enum() []any
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// enumValues returns the values allowed by the type of value when it implements Enum.
+func enumValues(value reflect.Value) ([]any, bool) {
+	if value.CanAddr() && !value.Type().Implements(enumType) {
+		value = value.Addr()
+	}
+	if !value.CanInterface() || !value.Type().Implements(enumType) {
+		return nil, false
+	}
+	return value.Interface().(Enum).Enum(), true
+}
+
+// inRule returns a rule checking that the value is one of its parameters, or none of them when negate is set.
+// Numbers compare numerically, so in:1,2 accepts 2.0, and fold ignores the case of strings.
+func inRule(negate, fold bool) RuleFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) bool {
+		found := false
+		for _, param := range params {
+			if fold && value.Kind() == reflect.String {
+				found = strings.EqualFold(value.String(), param)
+			} else {
+				c, ok := compareValues(value, reflect.ValueOf(param))
+				found = ok && c == 0
+			}
+			if found {
+				break
+			}
+		}
+		return found != negate
+	}
+}
+
+// listMessage renders the parameters of a rule as a list such as draft, published.
+func listMessage(key string) messageFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+		return key, []string{strings.Join(params, ", ")}
+	}
+}
+
+// equalsAny reports whether value, written as text, is one of values.
+func equalsAny(value reflect.Value, values []string) bool {
+	value = indirect(value)
+	text := ""
+	if value.IsValid() && value.CanInterface() && !isNil(value) {
+		text = fmt.Sprint(value.Interface())
+	}
+	for _, v := range values {
+		if v == text {
+			return true
+		}
+	}
+	return false
+}
+
+func check(isNot func(v reflect.Value) bool) RuleFunc {
+	return func(value reflect.Value, _ []string, _ *RuleContext) bool {
+		return !isNot(value)
+	}
+}
+
+func checkParam(isNot func(v reflect.Value, comparable string) bool) RuleFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) bool {
+		return !isNot(value, param(params, 0))
+	}
+}
+
+func checkRange(isNot func(v reflect.Value, min, max string) bool) RuleFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) bool {
+		return !isNot(value, param(params, 0), param(params, 1))
+	}
+}
+
+// checkFile applies isNot to file values only, other values pass.
+func checkFile(isNot func(v reflect.Value, params []string) bool) RuleFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) bool {
+		if _, ok := fileHeader(value); !ok {
+			return true
+		}
+		return !isNot(value, params)
+	}
+}
+
+func fileTypeMessage(key, typedKey string) messageFunc {
+	return func(value reflect.Value, params []string, _ *RuleContext) (string, []string) {
+		if len(params) == 0 {
+			return key, nil
+		}
+		return typedKey, []string{strings.Join(params, ",")}
+	}
+}
+
+func fileHeader(value reflect.Value) (*multipart.FileHeader, bool) {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil, false
+	}
+	fh, ok := value.Interface().(*multipart.FileHeader)
+	return fh, ok && fh != nil
+}
+
+func param(params []string, i int) string {
+	if i < len(params) {
+		return params[i]
+	}
+	return ""
+}
+
+// kindGroup returns the locale message group of value: numeric, string, slice or file.
+func kindGroup(value reflect.Value) string {
+	if _, ok := fileHeader(value); ok {
+		return "file"
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "slice"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return "numeric"
+	}
+	return "string"
+}
+
+func countVerbs(format string) int {
+	return strings.Count(format, "%") - 2*strings.Count(format, "%%")
+}
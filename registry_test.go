@@ -0,0 +1,97 @@
+package validata
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	RegisterRule("even", func(value reflect.Value, _ []string, _ *RuleContext) bool {
+		return value.Int()%2 == 0
+	}, RuleOptions{
+		Kinds:    intKinds,
+		Messages: map[string]string{"en": "The %s must be even.", "fr": "Le champ %s doit être pair."},
+	})
+	RegisterRule("prefixed", func(value reflect.Value, params []string, ctx *RuleContext) bool {
+		prefix := ctx.Parent().FieldByName("Prefix").String()
+		return len(value.String()) >= len(prefix) && value.String()[:len(prefix)] == prefix
+	}, RuleOptions{
+		Kinds:      stringKinds,
+		MessageKey: "invalid",
+	})
+	RegisterPattern("slug", `^[a-z0-9]+(-[a-z0-9]+)*$`)
+}
+
+func TestRegisterRule(t *testing.T) {
+	type request struct {
+		Count  int      `json:"count" validate:"even"`
+		Counts []int    `json:"counts" validate:"even"`
+		Name   string   `json:"name" validate:"even"`
+		Prefix string   `json:"prefix" validate:"_"`
+		Code   string   `json:"code" validate:"prefixed"`
+		Codes  []string `json:"codes" validate:"prefixed>Bad code"`
+	}
+	err := New().Validate(&request{
+		Count:  3,
+		Counts: []int{2, 5},
+		Name:   "odd",
+		Prefix: "GH",
+		Code:   "TG-1",
+		Codes:  []string{"GH-1", "TG-2"},
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := map[string]string{
+		"count":    "The count must be even.",
+		"counts.1": "The counts (2) must be even.",
+		"code":     "The code is invalid.",
+		"codes.1":  "Bad code",
+	}
+	if fields := errs.Fields(); len(fields) != len(expected) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+	if rule := errs.All()[0].Rule; rule != "even" {
+		t.Errorf("unexpected rule %q", rule)
+	}
+}
+
+func TestRegisterRuleMap(t *testing.T) {
+	err := New(WithLocale(LocaleFR)).ValidateMap(map[string]any{"total": 3}, map[string]string{"total": "even"})
+	if err == nil || err.(*ValidationErrors).First("total") != "Le champ total doit être pair." {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestRegisterPattern(t *testing.T) {
+	type request struct {
+		Slug  string `json:"slug" validate:"regex:slug"`
+		Code  string `json:"code" validate:"regex:'^(GH|TG)-\\d{1,3}$'>'Codes look like: GH-1 | TG-1'"`
+		Year  string `json:"year" validate:"regex:'^\\d{4}$'"`
+		Quote string `json:"quote" validate:"regex:'^[^\\']*$'"`
+	}
+	expectErrors(t, New().Validate(&request{Slug: "my-post", Code: "GH-233", Year: "2024", Quote: "fine"}), nil)
+	expectErrors(t, New().Validate(&request{Slug: "My post", Code: "NG-1", Year: "24", Quote: "don't"}), map[string]string{
+		"slug":  "The slug format is invalid.",
+		"code":  "Codes look like: GH-1 | TG-1",
+		"year":  "The year format is invalid.",
+		"quote": "The quote format is invalid.",
+	})
+	err := New(WithLocale(LocaleFR)).Var("a b", "regex:slug")
+	expectErrors(t, err, map[string]string{"value": "Le format du champ value n'est pas valide."})
+
+	var tagErr *TagError
+	if err := New().Var("hello-slgu", "regex:slgu"); !errors.As(err, &tagErr) || tagErr.Pos != 6 {
+		t.Errorf("expected an unknown pattern name to be a *TagError, got %v", err)
+	}
+	if err := New().Var("a", "regex:'[a-'"); !errors.As(err, &tagErr) || tagErr.Pos != 6 {
+		t.Errorf("expected a malformed expression to be a *TagError, got %v", err)
+	}
+}
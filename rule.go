@@ -1,6 +1,8 @@
 package validata
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,12 +12,31 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gabriel-vasile/mimetype"
 )
 
+var (
+	intRgx           = regexp.MustCompile(`^(?:[-]?(?:0|[1-9][0-9]*))$`)
+	uintRgx          = regexp.MustCompile(`^[1-9]\d+$`)
+	floatRgx         = regexp.MustCompile(`^[-+]?[0-9]*\.?[0-9]+([eE][-+]?[0-9]+)?$`)
+	alphaRgx         = regexp.MustCompile(`^[a-zA-Z]+$`)
+	alphanumericRgx  = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	numericRgx       = regexp.MustCompile(`^[0-9]+$`)
+	stringRgx        = regexp.MustCompile(`^[0-9a-zA-Z-+ .]+$`)
+	asciiRgx         = regexp.MustCompile(`[\x00-\x7F]+`)
+	phoneRgx         = regexp.MustCompile(`^0\d{9}$`)
+	phoneWithCodeRgx = regexp.MustCompile(`^\+\d{12}$`)
+	ghCardRgx        = regexp.MustCompile(`^GHA-\d{9}-\d{1}$`)
+	ghGPSRgx         = regexp.MustCompile(`[A-Z]{2}-\d{1,4}-\d{4}$`)
+	fileSizeRgx      = regexp.MustCompile(`^([1-9]|[1-9][0-9]+)(kb|KB|mb|MB|gb|GB|tb|TB)$`)
+)
+
 func isEmpty(v reflect.Value) bool {
 	switch v.Kind() {
+	case reflect.Invalid:
+		return true
 	case reflect.String, reflect.Array:
 		return v.Len() == 0
 	case reflect.Map, reflect.Slice:
@@ -33,40 +54,130 @@ func isEmpty(v reflect.Value) bool {
 	}
 	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
 }
+
+// isNil reports whether v holds no value: an invalid value or a nil pointer, interface, map or slice.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
 func isNotInt(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^(?:[-]?(?:0|[1-9][0-9]*))$`)
-	return !rgx.MatchString(fmt.Sprintf("%d", v.Interface()))
+	return !intRgx.MatchString(fmt.Sprintf("%d", v.Interface()))
 }
 func isNotUint(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[1-9]\d+$`)
-	return !rgx.MatchString(fmt.Sprintf("%d", v.Interface()))
+	return !uintRgx.MatchString(fmt.Sprintf("%d", v.Interface()))
 }
 func isNotFloat(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[-+]?[0-9]*\.?[0-9]+([eE][-+]?[0-9]+)?$`)
-	return !rgx.MatchString(fmt.Sprintf("%.2f", v.Interface()))
+	return !floatRgx.MatchString(fmt.Sprintf("%.2f", v.Interface()))
 }
 func isNotAlpha(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[a-zA-Z]+$`)
-	return !rgx.MatchString(v.String())
+	return !alphaRgx.MatchString(v.String())
 }
 func isNotAlphanumeric(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[a-zA-Z0-9]+$`)
-	return !rgx.MatchString(v.String())
+	return !alphanumericRgx.MatchString(v.String())
 }
 func isNotNumeric(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[0-9]+$`)
-	return !rgx.MatchString(v.String())
+	return !numericRgx.MatchString(v.String())
 }
 func isNotString(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^[0-9a-zA-Z-+ .]+$`)
-	return !rgx.MatchString(v.String())
+	return !stringRgx.MatchString(v.String())
 }
 func isNotSame(v1, v2 reflect.Value) bool {
-	return !(strings.TrimSpace(v1.String()) == strings.TrimSpace(v2.String()))
+	c, ok := compareValues(v1, v2)
+	return !ok || c != 0
+}
+
+// compareValues compares v1 with v2 according to their kinds: numbers by value, dates in time order,
+// slices, arrays and maps by length and strings with compareStrings. A string compared with a number
+// is read as a number.
+// ok is false when the values cannot be compared, e.g. a number and a slice.
+func compareValues(v1, v2 reflect.Value) (c int, ok bool) {
+	v1, v2 = indirect(v1), indirect(v2)
+	if isNil(v1) || isNil(v2) {
+		return 0, false
+	}
+	if v1.Type() == timeType || v2.Type() == timeType {
+		d1, ok1 := dateOf(v1, time.UTC)
+		d2, ok2 := dateOf(v2, time.UTC)
+		return d1.Compare(d2), ok1 && ok2
+	}
+	if v1.Kind() == reflect.String && v2.Kind() == reflect.String {
+		return compareStrings(v1.String(), v2.String()), true
+	}
+	if n1, ok1 := numberOf(v1); ok1 {
+		if n2, ok2 := numberOf(v2); ok2 {
+			return cmp.Compare(n1, n2), true
+		}
+	}
+	switch {
+	case v1.Kind() == reflect.Bool && v2.Kind() == reflect.Bool:
+		if v1.Bool() == v2.Bool() {
+			return 0, true
+		}
+		if v1.Bool() {
+			return 1, true
+		}
+		return -1, true
+	}
+	switch v1.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		switch v2.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			if c := cmp.Compare(v1.Len(), v2.Len()); c != 0 || !v1.CanInterface() || !v2.CanInterface() {
+				return c, true
+			}
+			if reflect.DeepEqual(v1.Interface(), v2.Interface()) {
+				return 0, true
+			}
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// compareStrings orders s1 and s2 as dates or numbers when both parse as such, and lexically otherwise.
+// Only identical strings are equal.
+func compareStrings(s1, s2 string) int {
+	if s1 == s2 {
+		return 0
+	}
+	c := 0
+	if d1, ok1 := parseDate(s1, time.UTC); ok1 {
+		if d2, ok2 := parseDate(s2, time.UTC); ok2 {
+			c = d1.Compare(d2)
+		}
+	} else if n1, err1 := strconv.ParseFloat(s1, 64); err1 == nil {
+		if n2, err2 := strconv.ParseFloat(s2, 64); err2 == nil {
+			c = cmp.Compare(n1, n2)
+		}
+	}
+	if c == 0 {
+		return strings.Compare(s1, s2)
+	}
+	return c
+}
+
+// numberOf returns the number held by v, a number or a string holding a number.
+func numberOf(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return n, err == nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
 }
 func isNotASCII(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`[\x00-\x7F]+`)
-	return !rgx.MatchString(v.String())
+	return !asciiRgx.MatchString(v.String())
 }
 func isNotEmail(v reflect.Value) bool {
 	if len(v.String()) < 6 || len(v.String()) > 254 {
@@ -89,12 +200,10 @@ func isNotEmail(v reflect.Value) bool {
 	return false
 }
 func isNotPhone(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^0\d{9}$`)
-	return !rgx.MatchString(v.String())
+	return !phoneRgx.MatchString(v.String())
 }
 func isNotPhoneWithCode(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^\+\d{12}$`)
-	return !rgx.MatchString(v.String())
+	return !phoneWithCodeRgx.MatchString(v.String())
 }
 func isNotUsername(v reflect.Value) bool {
 	if strings.Contains(v.String(), "@") {
@@ -106,12 +215,10 @@ func isNotUsername(v reflect.Value) bool {
 	return isNotPhone(v)
 }
 func isNotGHCard(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`^GHA-\d{9}-\d{1}$`)
-	return !rgx.MatchString(v.String())
+	return !ghCardRgx.MatchString(v.String())
 }
 func isNotGHGPS(v reflect.Value) bool {
-	rgx, _ := regexp.Compile(`[A-Z]{2}-\d{1,4}-\d{4}$`)
-	return !rgx.MatchString(v.String())
+	return !ghGPSRgx.MatchString(v.String())
 }
 func isNotMin(v reflect.Value, comparable string) bool {
 	switch v.Kind() {
@@ -259,14 +366,34 @@ func isNotMimes(v reflect.Value, mimes string) bool {
 	}
 	return false
 }
-func isNotUnique(dbConfig *Database, value, field, table string) bool {
+func parseFileSize(size string) (value, unit string) {
+	matches := fileSizeRgx.FindAllStringSubmatch(size, -1)
+	if matches == nil {
+		return size, ""
+	}
+	return matches[0][1], strings.ToLower(matches[0][2])
+}
+func isNotFileSize(fh *multipart.FileHeader, size string) bool {
+	value, unit := parseFileSize(size)
+	size64, _ := strconv.ParseInt(value, 10, 64)
+	switch unit {
+	case "kb":
+		return fh.Size > int64(kilobyte*size64)
+	case "mb":
+		return fh.Size > int64(megabyte*size64)
+	case "gb":
+		return fh.Size > int64(gigabyte*size64)
+	}
+	return false
+}
+func isNotUnique(ctx context.Context, dbConfig *Database, value, field, table string) bool {
 	db := connectDB(dbConfig)
 	defer db.Close()
 
 	dbField := snakeCase(field)
 	queryStr := fmt.Sprintf("SELECT %s FROM %s WHERE %s=?", dbField, table, dbField)
-	if err := db.QueryRow(queryStr, value).Scan(&value); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	if err := db.QueryRowContext(ctx, queryStr, value).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
 			return false
 		}
 		panic(err)
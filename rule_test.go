@@ -0,0 +1,315 @@
+package validata
+
+import (
+	"errors"
+	"mime/multipart"
+	"testing"
+	"time"
+)
+
+// expectErrors compares the messages reported by err with expected, keyed by path.
+func expectErrors(t *testing.T, err error, expected map[string]string) {
+	t.Helper()
+	if len(expected) == 0 {
+		if err != nil {
+			t.Errorf("expected no errors, got %v", err)
+		}
+		return
+	}
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if fields := errs.Fields(); len(fields) != len(expected) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+}
+
+func TestConditionalRequiredRules(t *testing.T) {
+	type request struct {
+		Status  string `json:"status"`
+		Active  *bool  `json:"active"`
+		Email   string `json:"email" validate:"required_without:phone"`
+		Phone   string `json:"phone" validate:"required_without_all:email,fax"`
+		Fax     string `json:"fax"`
+		Reason  string `json:"reason" validate:"required_if:status,rejected,cancelled"`
+		Comment string `json:"comment" validate:"required_unless:status,approved"`
+		Street  string `json:"street"`
+		City    string `json:"city" validate:"required_with:street,zip"`
+		Zip     string `json:"zip" validate:"required_with_all:street,city"`
+		Until   string `json:"until" validate:"required_if:active,true"`
+	}
+	active := true
+	tests := []struct {
+		name     string
+		request  request
+		expected map[string]string
+	}{
+		{"empty", request{}, map[string]string{
+			"email":   "The email field is required when phone is not present.",
+			"phone":   "The phone field is required when none of email / fax are present.",
+			"comment": "The comment field is required unless status is in approved.",
+		}},
+		{"conditions", request{Status: "cancelled", Active: &active, Phone: "0241234567", Street: "Main", City: "Accra"}, map[string]string{
+			"reason":  "The reason field is required when status is rejected, cancelled.",
+			"comment": "The comment field is required unless status is in approved.",
+			"zip":     "The zip field is required when street / city are present.",
+			"until":   "The until field is required when active is true.",
+		}},
+		{"satisfied", request{Status: "approved", Email: "contact@mail.com", Fax: "0241234567", Street: "Main", City: "Accra", Zip: "00233"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectErrors(t, New().Validate(&tt.request), tt.expected)
+		})
+	}
+
+	err := New(WithLocale(LocaleFR)).ValidateMap(map[string]any{"street": "Main"}, map[string]string{"city": "required_with:street"})
+	expectErrors(t, err, map[string]string{"city": "Le champ city est requis quand street est présent."})
+}
+
+func TestComparisonRules(t *testing.T) {
+	type request struct {
+		MinPrice float64  `json:"min_price" validate:"gte:0"`
+		MaxPrice float64  `json:"max_price" validate:"gt:min_price"`
+		Name     string   `json:"name" validate:"lt:5"`
+		Tags     []string `json:"tags" validate:"slice:lte:2"`
+		Stock    *uint    `json:"stock" validate:"gte:1"`
+		Limit    int      `json:"limit" validate:"lte:max_price"`
+		Ceiling  int      `json:"ceiling"`
+		Qty      int      `json:"qty" validate:"lte:ceiling"`
+	}
+	stock := uint(0)
+	err := New().Validate(&request{MinPrice: 10, MaxPrice: 10, Name: "Wood White", Tags: []string{"a", "b", "c"}, Stock: &stock, Limit: 11, Ceiling: 3, Qty: 5})
+	expectErrors(t, err, map[string]string{
+		"max_price": "The max price must be greater than 10.",
+		"name":      "The name must be less than 5 characters.",
+		"tags":      "The tags must not have more than 2 items.",
+		"stock":     "The stock must be greater than or equal to 1.",
+		"limit":     "The limit must be less than or equal to 10.",
+		"qty":       "The qty must be less than or equal to 3.",
+	})
+	err = New(WithLocale(LocaleFR)).Validate(&request{MinPrice: 1, MaxPrice: 2, Limit: 2})
+	expectErrors(t, err, nil)
+
+	file := &multipart.FileHeader{Filename: "photo.png", Size: 3 * megabyte}
+	err = New().VarWithValue(file, nil, "lt:2")
+	expectErrors(t, err, map[string]string{"value": "The value must be less than 2 megabytes."})
+}
+
+func TestDateRules(t *testing.T) {
+	type request struct {
+		Birthday  string     `json:"birthday" validate:"date|before:-18y"`
+		StartDate string     `json:"start_date" validate:"date_format:2006-01-02|after_or_equal:today"`
+		EndDate   time.Time  `json:"end_date" validate:"after:start_date|before_or_equal:+30d"`
+		Reminder  *time.Time `json:"reminder" validate:"required|before:end_date"`
+		Posted    string     `json:"posted" validate:"date_format:Jan 2, 2006"`
+	}
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	validator := New(WithClock(func() time.Time { return now }))
+	reminder := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	err := validator.Validate(&request{
+		Birthday:  "2010-05-01",
+		StartDate: "2024-03-09",
+		EndDate:   time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		Reminder:  &reminder,
+		Posted:    "2024-03-10",
+	})
+	expectErrors(t, err, map[string]string{
+		"birthday":   "The birthday must be a date before 2006-03-10.",
+		"start_date": "The start date must be a date after or equal to 2024-03-10.",
+		"end_date":   "The end date must be a date before or equal to 2024-04-09.",
+		"posted":     "The posted does not match the format Jan 2, 2006.",
+	})
+
+	err = validator.Validate(&request{
+		Birthday:  "10/05/2000",
+		StartDate: "2024-03-10",
+		EndDate:   time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Reminder:  &reminder,
+		Posted:    "Mar 10, 2024",
+	})
+	expectErrors(t, err, map[string]string{
+		"birthday": "The birthday is not a valid date.",
+		"end_date": "The end date must be a date after start date.",
+		"reminder": "The reminder must be a date before end date.",
+	})
+
+	err = New(WithLocale(LocaleFR), WithClock(func() time.Time { return now })).Var("2024-03-11", "before:tomorrow")
+	expectErrors(t, err, map[string]string{"value": "Le champ value doit être une date antérieure à 2024-03-11."})
+	err = validator.Var("2024-03-11", "before:+3h")
+	expectErrors(t, err, map[string]string{"value": "The value must be a date before 2024-03-10 18:30."})
+}
+
+func TestFieldComparisonRules(t *testing.T) {
+	type request struct {
+		Password             string    `json:"password" validate:"confirmed|different:username"`
+		PasswordConfirmation string    `json:"password_confirmation"`
+		Username             string    `json:"username"`
+		Pin                  string    `json:"pin" validate:"same:pin_repeat"`
+		PinRepeat            string    `json:"pin_repeat"`
+		MinAge               int       `json:"min_age"`
+		MaxAge               int       `json:"max_age" validate:"gt_field:min_age"`
+		Price                float64   `json:"price" validate:"lt_field:max_age"`
+		Version              string    `json:"version" validate:"gt_field:min_version"`
+		MinVersion           string    `json:"min_version"`
+		Start                time.Time `json:"start" validate:"lt_field:end"`
+		End                  string    `json:"end"`
+		Tags                 []string  `json:"tags" validate:"slice:eq_field:labels"`
+		Labels               []string  `json:"labels"`
+		Count                uint      `json:"count" validate:"ne_field:max_age|same:min_age"`
+	}
+	valid := request{
+		Password: "secret", PasswordConfirmation: "secret", Username: "wood",
+		Pin: "0123", PinRepeat: "0123",
+		MinAge: 18, MaxAge: 30, Price: 29.5,
+		Version: "10", MinVersion: "9",
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: "2024-01-02",
+		Tags: []string{"a", "b"}, Labels: []string{"a", "b"},
+		Count: 18,
+	}
+	expectErrors(t, New().Validate(&valid), nil)
+
+	invalid := valid
+	invalid.PasswordConfirmation = "secret "
+	invalid.Pin = "123"
+	invalid.MaxAge = 18
+	invalid.Price = 18
+	invalid.Version = "8"
+	invalid.End = "2023-12-31"
+	invalid.Labels = []string{"a", "c"}
+	invalid.Count = 18
+	expectErrors(t, New().Validate(&invalid), map[string]string{
+		"password": "The password confirmation does not match.",
+		"pin":      "The pin and pin_repeat must match.",
+		"max_age":  "The max age must be greater than min age.",
+		"price":    "The price must be less than max age.",
+		"version":  "The version must be greater than min version.",
+		"start":    "The start must be less than end.",
+		"tags":     "The tags must be equal to labels.",
+		"count":    "The count must not be equal to max age.",
+	})
+
+	invalid = valid
+	invalid.Password = "wood"
+	invalid.PasswordConfirmation = "wood"
+	expectErrors(t, New(WithLocale(LocaleFR)).Validate(&invalid), map[string]string{
+		"password": "Les champs password et username doivent être différents.",
+	})
+}
+
+func TestFieldReferences(t *testing.T) {
+	type contact struct {
+		Password string `json:"password" validate:"different:../password"`
+		Confirm  string `json:"confirm" validate:"same:../password"`
+		Email    string `json:"email" validate:"required_with:$.budget.min"`
+	}
+	type item struct {
+		Price float64 `json:"price" validate:"gte:$.budget.min|lte:$.budget.max"`
+		Qty   int     `json:"qty" validate:"required_unless:../status,draft"`
+	}
+	type budget struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max" validate:"gt_field:min"`
+	}
+	type request struct {
+		Status   string   `json:"status"`
+		Password string   `json:"password"`
+		Budget   budget   `json:"budget" validate:"_"`
+		Contact  *contact `json:"contact" validate:"_"`
+		Items    []item   `json:"items" validate:"_"`
+	}
+	valid := request{
+		Status: "draft", Password: "secret",
+		Budget:  budget{Min: 10, Max: 100},
+		Contact: &contact{Password: "other", Confirm: "secret", Email: "contact@mail.com"},
+		Items:   []item{{Price: 10}, {Price: 100, Qty: 1}},
+	}
+	expectErrors(t, New().Validate(&valid), nil)
+
+	invalid := valid
+	invalid.Status = "open"
+	invalid.Budget.Max = 5
+	invalid.Contact = &contact{Password: "secret", Confirm: "other"}
+	invalid.Items = []item{{Price: 1}}
+	expectErrors(t, New().Validate(&invalid), map[string]string{
+		"budget.max":       "The max must be greater than min.",
+		"contact.password": "The password and password must be different.",
+		"contact.confirm":  "The confirm and password must match.",
+		"contact.email":    "The email field is required when budget min is present.",
+		"items.0.price":    "The price must be greater than or equal to 10.",
+		"items.0.qty":      "The qty field is required unless status is in draft.",
+	})
+
+	err := New().ValidateMap(map[string]any{
+		"budget": map[string]any{"min": 10},
+		"items":  []any{map[string]any{"price": 5}},
+	}, map[string]string{"items.*.price": "gte:$.budget.min", "budget.max": "required_with:min"})
+	expectErrors(t, err, map[string]string{
+		"items.0.price": "The price must be greater than or equal to 10.",
+		"budget.max":    "The max field is required when min is present.",
+	})
+}
+
+type testStatus string
+
+const (
+	testStatusDraft     testStatus = "draft"
+	testStatusPublished testStatus = "published"
+)
+
+func (testStatus) Enum() []any { return []any{testStatusDraft, testStatusPublished} }
+
+type testLevel int
+
+func (*testLevel) Enum() []any { return []any{1, 2, 3} }
+
+func TestInRules(t *testing.T) {
+	type request struct {
+		Role     string     `json:"role" validate:"in:admin,editor"`
+		Size     string     `json:"size" validate:"in_ci:S,M,L"`
+		Priority int        `json:"priority" validate:"in:1,2,3"`
+		Rating   float64    `json:"rating" validate:"not_in:0,1.5"`
+		Name     string     `json:"name" validate:"not_in_ci:admin,root"`
+		Status   testStatus `json:"status" validate:"enum"`
+		Level    testLevel  `json:"level" validate:"enum"`
+	}
+	valid := request{Role: "editor", Size: "m", Priority: 2, Rating: 2, Name: "wood", Status: testStatusPublished, Level: 3}
+	expectErrors(t, New().Validate(&valid), nil)
+
+	invalid := request{Role: "Editor", Size: "xl", Priority: 4, Rating: 1.5, Name: "Root", Status: "archived", Level: 4}
+	expectErrors(t, New().Validate(&invalid), map[string]string{
+		"role":     "The role must be one of: admin, editor.",
+		"size":     "The size must be one of: S, M, L.",
+		"priority": "The priority must be one of: 1, 2, 3.",
+		"rating":   "The rating must not be one of: 0, 1.5.",
+		"name":     "The name must not be one of: admin, root.",
+		"status":   "The status must be one of: draft, published.",
+		"level":    "The level must be one of: 1, 2, 3.",
+	})
+
+	type plain struct {
+		Status string `json:"status" validate:"enum"`
+	}
+	expectErrors(t, New().Validate(&plain{Status: "zzz"}), map[string]string{"status": "The status is invalid."})
+
+	err := New(WithLocale(LocaleFR)).Var("archived", "in:draft,published")
+	expectErrors(t, err, map[string]string{"value": "Le champ value doit être l'une des valeurs suivantes : draft, published."})
+}
+
+func TestConfirmedUntagged(t *testing.T) {
+	type request struct {
+		Password             string `json:"password" validate:"required|confirmed"`
+		PasswordConfirmation string `json:"password_confirmation"`
+	}
+	expectErrors(t, New().Validate(&request{Password: "secret", PasswordConfirmation: "secret"}), nil)
+	expectErrors(t, New().Validate(&request{Password: "secret", PasswordConfirmation: "secrets"}), map[string]string{
+		"password": "The password confirmation does not match.",
+	})
+}
@@ -0,0 +1,104 @@
+package validata
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validatable is implemented by structs holding invariants that span several fields,
+// e.g. an end date that must follow the start date.
+// ValidateStruct is called after the field rules, for the validated struct and for every
+// nested struct, including the items of slices of structs.
+type Validatable interface {
+	ValidateStruct(sl StructLevel)
+}
+
+// StructLevel gives ValidateStruct access to the struct being validated and lets it report errors.
+type StructLevel interface {
+	// Current returns the struct being validated.
+	Current() reflect.Value
+	// Root returns the value passed to Validate.
+	Root() reflect.Value
+	// Context returns the context of the validation.
+	Context() context.Context
+	// Locale returns the locale the messages are rendered in.
+	Locale() string
+	// ReportError reports field as failing rule. field is the JSON key of a field of the current
+	// struct, or a dotted path below it such as address.city. The message is looked up in the
+	// locale maps, or among the messages registered with RegisterMessage, under the rule name and rendered with params.
+	ReportError(field, rule string, params ...string)
+	// ReportMessage reports field as failing with a custom message.
+	ReportMessage(field, rule, message string)
+}
+
+type structLevel struct {
+	validation *validation
+	errors     []*FieldError
+}
+
+func (sl *structLevel) Current() reflect.Value {
+	return sl.validation.elemValue
+}
+
+func (sl *structLevel) Root() reflect.Value {
+	return sl.validation.root
+}
+
+func (sl *structLevel) Context() context.Context {
+	return sl.validation.ctx
+}
+
+func (sl *structLevel) Locale() string {
+	return sl.validation.getLocale()
+}
+
+func (sl *structLevel) ReportError(field, rule string, params ...string) {
+	sl.report(field, rule, "", params)
+}
+
+func (sl *structLevel) ReportMessage(field, rule, message string) {
+	sl.report(field, rule, message, nil)
+}
+
+func (sl *structLevel) report(field, rule, message string, params []string) {
+	v := sl.validation
+	keys := strings.Split(field, ".")
+	path := v.prefix
+	for _, key := range keys {
+		if i, err := strconv.Atoi(key); err == nil {
+			path = path.index(i)
+			continue
+		}
+		path = path.key(key)
+	}
+	fieldErr := &FieldError{
+		Field:   path.String(),
+		Key:     path.jsonKey(),
+		Rule:    rule,
+		Params:  params,
+		Message: message,
+		Locale:  v.getLocale(),
+		path:    path,
+	}
+	if message == "" {
+		value := v.lookupPath(v.elemValue, keys)
+		fieldErr.Message = formatMessage(v.getMessage(rule, value), formatFieldName(keys[len(keys)-1]), params)
+	}
+	sl.errors = append(sl.errors, fieldErr)
+}
+
+// structLevel runs ValidateStruct on the current struct when it implements Validatable.
+func (v *validation) structLevel() []*FieldError {
+	if !v.elemValue.CanAddr() || v.ctx.Err() != nil {
+		return nil
+	}
+	validatable, ok := v.elemValue.Addr().Interface().(Validatable)
+	if !ok {
+		return nil
+	}
+	sl := &structLevel{validation: v}
+	validatable.ValidateStruct(sl)
+	return sl.errors
+}
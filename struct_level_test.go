@@ -0,0 +1,67 @@
+package validata
+
+import (
+	"errors"
+	"testing"
+)
+
+type testPeriod struct {
+	Start string `json:"start" validate:"required"`
+	End   string `json:"end" validate:"_"`
+	Open  bool   `json:"open" validate:"_"`
+}
+
+func (p testPeriod) ValidateStruct(sl StructLevel) {
+	if !p.Open && p.End <= p.Start {
+		sl.ReportError("end", "end_after_start", p.Start)
+	}
+}
+
+type testBooking struct {
+	Period  testPeriod    `json:"period" validate:"_"`
+	Periods []*testPeriod `json:"periods" validate:"_"`
+	Guests  int           `json:"guests" validate:"required"`
+}
+
+func (b *testBooking) ValidateStruct(sl StructLevel) {
+	if b.Guests > 10 {
+		sl.ReportMessage("guests", "max_guests", "Too many guests")
+	}
+}
+
+func init() {
+	RegisterMessage("en", "end_after_start", "The %s must be after %s.")
+	RegisterMessage("fr", "end_after_start", "Le champ %s doit être après %s.")
+}
+
+func TestValidatable(t *testing.T) {
+	err := New().Validate(&testBooking{
+		Periods: []*testPeriod{
+			{Start: "2024-01-01", End: "2024-01-05"},
+			{Start: "2024-02-01", End: "2024-01-05"},
+			{Start: "2024-03-01", Open: true},
+		},
+		Guests: 12,
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := map[string]string{
+		"guests":          "Too many guests",
+		"periods.1.end":   "The end must be after 2024-02-01.",
+		"periods.0.start": "",
+		"periods.2.end":   "",
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+	if errs.All()[len(errs.All())-1].Rule != "max_guests" {
+		t.Errorf("expected the struct level error last, got %v", errs)
+	}
+	expectErrors(t, New(WithLocale(LocaleFR)).Validate(&testPeriod{Start: "2024-02-01", End: "2024-01-05"}), map[string]string{
+		"end": "Le champ end doit être après 2024-02-01.",
+	})
+}
@@ -0,0 +1,305 @@
+package validata
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TagRule is a rule of a validation tag, as returned by ParseTag.
+type TagRule struct {
+	// Name is the rule name, e.g. between for between:1,5.
+	Name string
+	// Params holds the rule parameters, unquoted and unescaped, e.g. ["1", "5"].
+	Params []string
+	// Message is the custom message following >, if any.
+	Message string
+	// Slice reports the deprecated slice: prefix.
+	Slice bool
+}
+
+// TagError describes a malformed validation tag.
+type TagError struct {
+	// Field is the struct field or map key holding the tag, if known.
+	Field string
+	// Tag is the malformed tag.
+	Tag string
+	// Pos is the byte offset in Tag at which the error was found.
+	Pos int
+	// Expected describes what the parser expected at Pos.
+	Expected string
+}
+
+// Error implements the error interface.
+func (e *TagError) Error() string {
+	found := "end of tag"
+	if e.Pos < len(e.Tag) {
+		found = strconv.QuoteRune(rune(e.Tag[e.Pos]))
+	}
+	field := ""
+	if e.Field != "" {
+		field = " of " + e.Field
+	}
+	return fmt.Sprintf("validata: invalid tag %q%s at position %d: expected %s, found %s", e.Tag, field, e.Pos, e.Expected, found)
+}
+
+// markers are the rules that change how the other rules apply. They take no parameters.
+var markers = map[string]bool{
+	"bail":      true,
+	"nullable":  true,
+	"omitempty": true,
+	"sometimes": true,
+	"dive":      true,
+	"keys":      true,
+	"endkeys":   true,
+	"values":    true,
+}
+
+// ParseTag parses a validation tag such as "required|between:1,5|regex:'^(a|b)$'>'Pick a or b'".
+//
+// Rules are separated by |. A rule name may be followed by : and parameters separated by commas,
+// then by > and a custom message running up to the next |. A parameter or message starting with a single
+// quote runs up to the closing quote, so it may hold |, >, : and commas; \' stands for a quote inside it.
+// Outside quotes, \|, \>, \, and \' stand for the character itself and other backslashes are kept.
+// The parameter of a regex rule is compiled when parsed: a quoted expression, or else the name of a
+// pattern registered with RegisterPattern.
+//
+// Rule names must be built-in, registered with RegisterRule or one of the markers such as dive and omitempty.
+// The name _ is accepted too, so that a tag such as "_" marks a field validated without rules.
+// It returns a *TagError for a malformed tag, an unknown rule name or a rule given too few parameters.
+func ParseTag(tag string) ([]TagRule, error) {
+	parsed, err := parseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]TagRule, 0, len(parsed))
+	for _, rule := range parsed {
+		rules = append(rules, TagRule{Name: rule.name, Params: rule.params, Message: rule.customMsg, Slice: rule.slice})
+	}
+	return rules, nil
+}
+
+func parseTag(tag string) ([]*parsedRule, *TagError) {
+	if tag == "" {
+		return nil, nil
+	}
+	p := &tagParser{tag: tag}
+	var rules []*parsedRule
+	keys := -1
+	for {
+		start := p.pos
+		rule, err := p.rule()
+		if err != nil {
+			return nil, err
+		}
+		switch rule.name {
+		case "keys":
+			if keys >= 0 {
+				return nil, p.errorAt(start, "endkeys")
+			}
+			keys = start
+		case "endkeys":
+			if keys < 0 {
+				return nil, p.errorAt(start, "keys before endkeys")
+			}
+			keys = -1
+		}
+		rules = append(rules, rule)
+		if p.eof() {
+			break
+		}
+		p.pos++ // |
+	}
+	if keys >= 0 {
+		return nil, p.errorAt(len(tag), "endkeys")
+	}
+	return rules, nil
+}
+
+type tagParser struct {
+	tag string
+	pos int
+}
+
+func (p *tagParser) eof() bool {
+	return p.pos >= len(p.tag)
+}
+
+func (p *tagParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.tag[p.pos]
+}
+
+func (p *tagParser) errorAt(pos int, expected string) *TagError {
+	return &TagError{Tag: p.tag, Pos: pos, Expected: expected}
+}
+
+// rule parses [slice:]name[:params][>message].
+func (p *tagParser) rule() (*parsedRule, *TagError) {
+	rule := &parsedRule{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "slice" && p.peek() == ':' {
+		p.pos++
+		rule.slice = true
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	rule.name = name
+	rule.def = lookupRule(name)
+	if rule.def == nil && !markers[name] && name != "_" {
+		return nil, p.errorAt(p.pos-len(name), "a known rule name")
+	}
+	if p.peek() == ':' {
+		if markers[name] {
+			return nil, p.errorAt(p.pos, "'|' after "+name)
+		}
+		p.pos++
+		var starts []int
+		var quoted []bool
+		for {
+			starts, quoted = append(starts, p.pos), append(quoted, p.peek() == '\'')
+			param, err := p.token(",|>", "parameter")
+			if err != nil {
+				return nil, err
+			}
+			rule.params = append(rule.params, param)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+		if name == "regex" {
+			if len(starts) > 1 {
+				return nil, p.errorAt(starts[1]-1, "a single pattern, quoted if it holds commas")
+			}
+			if err := p.pattern(rule, starts[0], quoted[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if rule.def != nil && len(rule.params) < rule.def.params {
+		expected := "':'"
+		if len(rule.params) > 0 {
+			expected = "','"
+		}
+		return nil, p.errorAt(p.pos, fmt.Sprintf("%s and %d more parameter(s) for %s", expected, rule.def.params-len(rule.params), name))
+	}
+	if p.peek() == '>' {
+		p.pos++
+		if rule.customMsg, err = p.token("|", "message"); err != nil {
+			return nil, err
+		}
+	}
+	if !p.eof() && p.peek() != '|' {
+		return nil, p.errorAt(p.pos, "'|'")
+	}
+	return rule, nil
+}
+
+// pattern binds a regex rule to its pattern: the expression it quotes, such as regex:'^[a-z]+$',
+// or else the pattern registered under the name it gives, such as regex:slug.
+func (p *tagParser) pattern(rule *parsedRule, pos int, quoted bool) *TagError {
+	var rgx *regexp.Regexp
+	if quoted {
+		var err error
+		if rgx, err = regexp.Compile(rule.params[0]); err != nil {
+			return p.errorAt(pos, "a valid regular expression ("+err.Error()+")")
+		}
+	} else {
+		var ok bool
+		if rgx, ok = lookupPattern(rule.params[0]); !ok {
+			return p.errorAt(pos, "a registered pattern name or a quoted expression")
+		}
+	}
+	rule.fn = func(value reflect.Value, _ []string, _ *RuleContext) bool {
+		return rgx.MatchString(value.String())
+	}
+	return nil
+}
+
+// name parses a rule name made of letters, digits, _, - and dots.
+func (p *tagParser) name() (string, *TagError) {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.') {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorAt(start, "rule name")
+	}
+	return p.tag[start:p.pos], nil
+}
+
+// token parses a parameter or a message ending before one of stops, quoted or not.
+func (p *tagParser) token(stops, what string) (string, *TagError) {
+	var b strings.Builder
+	if p.peek() == '\'' {
+		open := p.pos
+		p.pos++
+		for {
+			if p.eof() {
+				return "", p.errorAt(open, "closing quote")
+			}
+			c := p.tag[p.pos]
+			if c == '\\' && p.pos+1 < len(p.tag) && p.tag[p.pos+1] == '\'' {
+				b.WriteByte('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			if c == '\'' {
+				break
+			}
+			b.WriteByte(c)
+		}
+		if !p.eof() && strings.IndexByte(stops, p.peek()) < 0 {
+			return "", p.errorAt(p.pos, expectedStops(stops))
+		}
+		return b.String(), nil
+	}
+	start := p.pos
+	for !p.eof() && strings.IndexByte(stops, p.peek()) < 0 {
+		c := p.tag[p.pos]
+		if c == '\\' && p.pos+1 < len(p.tag) && strings.IndexByte(`|>,'`, p.tag[p.pos+1]) >= 0 {
+			c = p.tag[p.pos+1]
+			p.pos++
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorAt(start, what)
+	}
+	return b.String(), nil
+}
+
+// expectedStops describes the characters that may follow a quoted token.
+func expectedStops(stops string) string {
+	names := make([]string, 0, len(stops)+1)
+	for i := 0; i < len(stops); i++ {
+		names = append(names, strconv.QuoteRune(rune(stops[i])))
+	}
+	return strings.Join(names, ", ") + " or end of tag"
+}
+
+// tagErrors records the first malformed tag met while validating.
+type tagErrors struct {
+	once sync.Once
+	err  error
+}
+
+func (e *tagErrors) set(err error) {
+	e.once.Do(func() { e.err = err })
+}
@@ -0,0 +1,95 @@
+package validata
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected []TagRule
+	}{
+		{"", []TagRule{}},
+		{"_", []TagRule{{Name: "_"}}},
+		{"required|between:1,5>Pick 1 to 5", []TagRule{{Name: "required"}, {Name: "between", Params: []string{"1", "5"}, Message: "Pick 1 to 5"}}},
+		{"slice:max:2|date_format:15:04", []TagRule{{Name: "max", Params: []string{"2"}, Slice: true}, {Name: "date_format", Params: []string{"15:04"}}}},
+		{`regex:'^(a|b):\d$'>'Use a: or b | c'`, []TagRule{{Name: "regex", Params: []string{`^(a|b):\d$`}, Message: "Use a: or b | c"}}},
+		{`in:'a,b',c\,d,'it\'s'`, []TagRule{{Name: "in", Params: []string{"a,b", "c,d", "it's"}}}},
+		{`required>Can't be empty\|blank`, []TagRule{{Name: "required", Message: "Can't be empty|blank"}}},
+		{"keys|alpha|endkeys|dive|email", []TagRule{{Name: "keys"}, {Name: "alpha"}, {Name: "endkeys"}, {Name: "dive"}, {Name: "email"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			rules, err := ParseTag(tt.tag)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rules) != len(tt.expected) || (len(rules) > 0 && !reflect.DeepEqual(rules, tt.expected)) {
+				t.Errorf("got %+v, want %+v", rules, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTagErrors(t *testing.T) {
+	tests := []struct {
+		tag      string
+		pos      int
+		expected string
+	}{
+		{"from:1", 6, "',' and 1 more parameter(s) for from"},
+		{"required|between>Out of range", 16, "':' and 2 more parameter(s) for between"},
+		{"requried|min:3", 0, "a known rule name"},
+		{"required|slice:mni:3", 15, "a known rule name"},
+		{"required||min:3", 9, "rule name"},
+		{"required|", 9, "rule name"},
+		{"min:", 4, "parameter"},
+		{"between:1,|max:3", 10, "parameter"},
+		{"required>", 9, "message"},
+		{"regex:'^a|b$", 6, "closing quote"},
+		{"in:'a'b", 6, "',', '|', '>' or end of tag"},
+		{"required extra", 8, "'|'"},
+		{"dive:1", 4, "'|' after dive"},
+		{"keys|alpha", 10, "endkeys"},
+		{"alpha|endkeys", 6, "keys before endkeys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			_, err := ParseTag(tt.tag)
+			var tagErr *TagError
+			if !errors.As(err, &tagErr) {
+				t.Fatalf("expected *TagError, got %v", err)
+			}
+			if tagErr.Pos != tt.pos || tagErr.Expected != tt.expected {
+				t.Errorf("got position %d expecting %q, want position %d expecting %q", tagErr.Pos, tagErr.Expected, tt.pos, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMalformedTags(t *testing.T) {
+	type inner struct {
+		Age int `json:"age" validate:"from:1"`
+	}
+	type request struct {
+		Name  string `json:"name" validate:"required"`
+		Inner *inner `json:"inner" validate:"_"`
+	}
+	expected := `validata: invalid tag "from:1" of inner.Age at position 6: expected ',' and 1 more parameter(s) for from, found end of tag`
+	if err := Compile[request](); err == nil || err.Error() != expected {
+		t.Errorf("Compile: got %v, want %s", err, expected)
+	}
+	var tagErr *TagError
+	if err := New().Validate(&request{Inner: &inner{Age: 3}}); !errors.As(err, &tagErr) || tagErr.Field != "inner.Age" {
+		t.Errorf("Validate: expected a *TagError for inner.Age, got %v", err)
+	}
+	if err := New().Var("wood", "required|in:'a"); !errors.As(err, &tagErr) || tagErr.Pos != 12 {
+		t.Errorf("Var: expected a *TagError at position 12, got %v", err)
+	}
+	err := New().ValidateMap(map[string]any{"age": 3}, map[string]string{"age": "between:1"})
+	if !errors.As(err, &tagErr) || tagErr.Field != "age" {
+		t.Errorf("ValidateMap: expected a *TagError for age, got %v", err)
+	}
+}
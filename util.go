@@ -2,10 +2,16 @@ package validata
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	// import github.com/go-sql-driver/mysql
 	_ "github.com/go-sql-driver/mysql"
@@ -13,20 +19,19 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func getRuleAndMsg(r string) (rule, customMsg string) {
-	if strings.Contains(r, ">") {
-		rcm := strings.SplitN(r, ">", 2)
-		rule = rcm[0]
-		customMsg = rcm[1]
-	} else {
-		rule = r
-	}
-	return
-}
+// formatFieldName turns a field key into the label used in messages, e.g. "min price" for minPrice.
+// Underscores and hyphens separate words too, so that keys read from a key tag such as
+// min_price or min-price give "min price" rather than "min _price".
 func formatFieldName(field string) string {
 	var text string
 	for i := 0; i < len(field); i++ {
 		c := string([]byte{field[i]})
+		if c == "_" || c == "-" {
+			if len(text) != 0 && !strings.HasSuffix(text, " ") {
+				text += " "
+			}
+			continue
+		}
 		if c == strings.ToUpper(c) {
 			if len(text) != 0 {
 				text += " "
@@ -83,6 +88,11 @@ type Database struct {
 	SSLMode  string
 }
 
+// apply lets a *Database be passed to New as an Option.
+func (d *Database) apply(v *Validator) {
+	v.dbConfig = d
+}
+
 func connectDB(config *Database) *sql.DB {
 	switch config.Driver {
 	case DriverPostgres:
@@ -124,3 +134,255 @@ func connectDB(config *Database) *sql.DB {
 		panic(fmt.Sprintf("[db connection error]: provide db driver. validata currently support %s and %s drivers.", DriverMysql, DriverPostgres))
 	}
 }
+
+type parsedRule struct {
+	def       *ruleDef
+	fn        RuleFunc // overrides def.fn for rules bound to their parameters when parsed, like regex
+	name      string
+	params    []string
+	customMsg string
+	slice     bool
+}
+
+func formatMessage(format, field string, values []string) string {
+	args := make([]any, 0, len(values)+1)
+	args = append(args, field)
+	for _, value := range values {
+		if len(args) >= countVerbs(format) {
+			break
+		}
+		args = append(args, value)
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// nestedStruct returns a pointer to the struct held by value, if any.
+// Structs that are not addressable, such as map values, are copied.
+func nestedStruct(value reflect.Value) (any, bool) {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct {
+		if !value.CanAddr() {
+			elem := reflect.New(value.Type())
+			elem.Elem().Set(value)
+			value = elem
+		} else {
+			value = value.Addr()
+		}
+	}
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct || scalarStruct(value.Elem().Type()) {
+		return nil, false
+	}
+	if _, ok := fileHeader(value); ok {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+// indirect returns the value held by an interface, and the value pointed to by a non-nil pointer
+// unless it is a struct, which nestedStruct validates through the pointer.
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface || (value.Kind() == reflect.Pointer && !value.IsNil() && !structPointer(value)) {
+		value = value.Elem()
+	}
+	return value
+}
+
+// structPointer reports whether value points to a struct validated field by field.
+func structPointer(value reflect.Value) bool {
+	return value.Elem().Kind() == reflect.Struct && !scalarStruct(value.Elem().Type())
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	valuerType        = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// scalarStruct reports whether the struct type typ holds a single value, like time.Time or sql.NullString.
+// Such structs are validated as values and their fields are not validated.
+func scalarStruct(typ reflect.Type) bool {
+	return typ == timeType || typ.Implements(textMarshalerType) || typ.Implements(valuerType)
+}
+
+type pathSegment struct {
+	key   string
+	index int
+}
+
+// fieldPath locates a value from the root of the validated input, e.g. contacts.0.email.
+type fieldPath []pathSegment
+
+func (p fieldPath) String() string {
+	keys := make([]string, 0, len(p))
+	for _, seg := range p {
+		keys = append(keys, seg.key)
+	}
+	return strings.Join(keys, ".")
+}
+
+func (p fieldPath) key(k string) fieldPath {
+	return append(append(make(fieldPath, 0, len(p)+1), p...), pathSegment{key: k, index: -1})
+}
+
+func (p fieldPath) index(i int) fieldPath {
+	return append(append(make(fieldPath, 0, len(p)+1), p...), pathSegment{key: strconv.Itoa(i), index: i})
+}
+
+// jsonKey returns the key of the closest named field, skipping slice indexes.
+func (p fieldPath) jsonKey() string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].index < 0 {
+			return p[i].key
+		}
+	}
+	return ""
+}
+
+type mapTarget struct {
+	path   fieldPath
+	value  reflect.Value
+	parent reflect.Value
+}
+
+func (t mapTarget) label(itemLabel string) string {
+	last := t.path[len(t.path)-1]
+	if last.index >= 0 && len(t.path) > 1 {
+		return fmt.Sprintf(itemLabel, formatFieldName(t.path[len(t.path)-2].key), last.index+1)
+	}
+	return formatFieldName(last.key)
+}
+
+// resolveMapPath expands a dotted rule key into every value it addresses.
+// A "*" segment matches all items of a slice or all keys of a map.
+func resolveMapPath(value reflect.Value, path fieldPath, keys []string) []mapTarget {
+	return resolveMapTargets(reflect.Value{}, value, path, keys)
+}
+
+func resolveMapTargets(parent, value reflect.Value, path fieldPath, keys []string) []mapTarget {
+	for value.IsValid() && value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if len(keys) == 0 {
+		return []mapTarget{{path: path, value: value, parent: parent}}
+	}
+	key, rest := keys[0], keys[1:]
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if key == "*" {
+			mapKeys := value.MapKeys()
+			sort.Slice(mapKeys, func(i, j int) bool { return mapKeys[i].String() < mapKeys[j].String() })
+			targets := make([]mapTarget, 0, len(mapKeys))
+			for _, k := range mapKeys {
+				targets = append(targets, resolveMapTargets(value, value.MapIndex(k), path.key(k.String()), rest)...)
+			}
+			return targets
+		}
+		return resolveMapTargets(value, value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())), path.key(key), rest)
+	case reflect.Slice, reflect.Array:
+		if key == "*" {
+			targets := make([]mapTarget, 0, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				targets = append(targets, resolveMapTargets(value, value.Index(i), path.index(i), rest)...)
+			}
+			return targets
+		}
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < value.Len() {
+			return resolveMapTargets(value, value.Index(i), path.index(i), rest)
+		}
+	}
+	if key == "*" {
+		return nil
+	}
+	return resolveMapTargets(value, reflect.Value{}, path.key(key), rest)
+}
+
+// hasMapPath reports whether every key of path exists in value, a map payload.
+func hasMapPath(value reflect.Value, path fieldPath) bool {
+	for _, seg := range path {
+		for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return false
+			}
+			value = value.MapIndex(reflect.ValueOf(seg.key).Convert(value.Type().Key()))
+			if !value.IsValid() {
+				return false
+			}
+		case reflect.Slice, reflect.Array:
+			if seg.index < 0 || seg.index >= value.Len() {
+				return false
+			}
+			value = value.Index(seg.index)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// setMapMessage stores msg in errMsg using the legacy error layout:
+// nested maps for nested keys, a list for slice items and "key.index" entries for slice of structs.
+// A field reporting several messages gets a list of messages, and a field with nested errors keeps its own
+// messages under its key in the nested map, e.g. {"contacts": {"contacts": "...", "contacts.0": {...}}}.
+func setMapMessage(errMsg map[string]any, path fieldPath, msg any) {
+	if len(path) == 0 {
+		path = fieldPath{}.key("")
+	}
+	key := path[0].key
+	if len(path) == 1 {
+		switch existing := errMsg[key].(type) {
+		case nil:
+			errMsg[key] = msg
+		case []any:
+			errMsg[key] = append(existing, msg)
+		case string:
+			errMsg[key] = []any{existing, msg}
+		case map[string]any:
+			// The field holds nested errors: keep its own messages under its key.
+			setMapMessage(existing, path, msg)
+		}
+		return
+	}
+	if path[1].index >= 0 && len(path) == 2 {
+		if nested, ok := errMsg[key].(map[string]any); ok {
+			nested[fmt.Sprintf("%s.%d", key, path[1].index)] = msg
+			return
+		}
+		switch existing := errMsg[key].(type) {
+		case []any:
+			errMsg[key] = append(existing, msg)
+		case string:
+			errMsg[key] = []any{existing, msg}
+		default:
+			errMsg[key] = []any{msg}
+		}
+		return
+	}
+	nested, ok := errMsg[key].(map[string]any)
+	if !ok {
+		nested = make(map[string]any)
+		if existing, ok := errMsg[key]; ok {
+			nested[key] = existing
+		}
+		errMsg[key] = nested
+	}
+	if path[1].index >= 0 {
+		itemKey := fmt.Sprintf("%s.%d", key, path[1].index)
+		item, ok := nested[itemKey].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			nested[itemKey] = item
+		}
+		setMapMessage(item, path[2:], msg)
+		return
+	}
+	setMapMessage(nested, path[1:], msg)
+}
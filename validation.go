@@ -1,16 +1,19 @@
 package validata
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"reflect"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/SeyramWood/validata/locale"
 )
@@ -20,6 +23,8 @@ const (
 	megabyte = kilobyte * kilobyte
 	gigabyte = megabyte * kilobyte
 
+	// LocaleEN constant variable for en locale
+	LocaleEN = "en"
 	// LocaleFR constant variable for fr locale
 	LocaleFR = "fr"
 	// DriverPostgres postgres driver for database connection
@@ -30,44 +35,245 @@ const (
 
 type message struct {
 	K string
-	V any
+	V []*FieldError
 }
 
+// Validator validates structs and maps against their rules.
+// A Validator holds configuration only, so a single instance can be shared across goroutines.
+type Validator struct {
+	locale           string
+	dbConfig         *Database
+	tagName          string
+	keyTagName       string
+	stopOnFirstError bool
+	collectAllErrors bool
+	flatErrors       *flatFormat
+	itemLabel        string
+	clock            func() time.Time
+}
+
+// ErrAborted is returned, wrapping the context error, when the context of a validation
+// is cancelled or its deadline is exceeded before the validation completes.
+var ErrAborted = errors.New("validata: validation aborted")
+
+// validation holds the state of a single Validate call.
 type validation struct {
-	jsonRes struct {
-		Status bool `json:"status"`
-		Errors any  `json:"errors"`
-	}
+	ctx       context.Context
+	validator *Validator
 	elem      any
 	elemType  reflect.Type
 	elemValue reflect.Value
+	root      reflect.Value
 	locale    string
-	dbConfig  *Database
+	rules     map[string]string
+	prefix    fieldPath
+	other     reflect.Value
+	filter    *pathFilter
+	present   map[string]bool
+	tagErr    *tagErrors
 }
 
-// New takes optional database connection configuration.
-// Validata use this configuration to connect to your database to check for exist field in validation.
-func New(config ...*Database) *validation {
-	instance := new(validation)
-	if config != nil {
-		instance.dbConfig = config[0]
+// New creates a Validator configured by the given options.
+// A *Database may be passed directly as an option, so New(&Database{...}) keeps working.
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		tagName:    "validate",
+		keyTagName: "json",
+		itemLabel:  "%s (%d)",
+		clock:      time.Now,
 	}
-	return instance
+	for _, opt := range opts {
+		if opt != nil {
+			opt.apply(v)
+		}
+	}
+	return v
 }
 
 // Validate performs validation on your input.
 // It takes struct pointer and optional locale parameters.
-func (v *validation) Validate(elem any, locale ...string) map[string]any {
+// It returns nil or a *ValidationErrors describing every failed field,
+// or a *TagError when a validation tag is malformed.
+// It panics when given a map pointer: maps are validated against rules with ValidateMap.
+//
+// Pointer fields are validated through the value they point to, and only a nil pointer is empty:
+// a pointer to a zero value satisfies required and is checked by the other rules.
+// The nullable rule skips the rules of a nil field, and omitempty skips the rules of a nil or zero field.
+func (v *Validator) Validate(elem any, locale ...string) error {
+	return v.ValidateContext(context.Background(), elem, locale...)
+}
+
+// ValidateContext performs validation on your input like Validate, passing ctx to every rule.
+// It stops early and returns an error wrapping ErrAborted and ctx.Err() when ctx is done.
+func (v *Validator) ValidateContext(ctx context.Context, elem any, locale ...string) error {
+	if value := reflect.ValueOf(elem); value.Kind() == reflect.Pointer && value.Type().Elem().Kind() == reflect.Map {
+		panic("validate: a map is validated against rules, use ValidateMap")
+	}
+	instance := v.newValidation(ctx, nil, locale...)
+	return instance.result(instance.validate(elem))
+}
+
+// ValidateMap performs validation on a map payload.
+// It takes the map, the rules keyed by dotted path (e.g. "contacts.*.email") and optional locale parameters.
+func (v *Validator) ValidateMap(elem map[string]any, rules map[string]string, locale ...string) error {
+	return v.ValidateMapContext(context.Background(), elem, rules, locale...)
+}
+
+// ValidateMapContext performs validation on a map payload like ValidateMap, passing ctx to every rule.
+func (v *Validator) ValidateMapContext(ctx context.Context, elem map[string]any, rules map[string]string, locale ...string) error {
+	instance := v.newValidation(ctx, rules, locale...)
+	return instance.result(instance.validate(&elem))
+}
+
+// Var validates a single value against rules using the rule grammar of the validate tag,
+// e.g. Var(email, "required|email"). Errors are reported under the path value,
+// or value.1 for the second item of a slice.
+func (v *Validator) Var(value any, rules string) error {
+	return v.VarWithValue(value, nil, rules)
+}
+
+// VarWithValue validates a single value against rules like Var.
+// Rules comparing against another field, such as same or match, compare against other.
+func (v *Validator) VarWithValue(value, other any, rules string) error {
+	instance := v.newValidation(context.Background(), nil)
+	instance.root = reflect.ValueOf(value)
+	instance.other = reflect.ValueOf(other)
+	parsed, err := compileRules(rules, "")
+	if err != nil {
+		return err
+	}
+	return instance.result(instance.validateValue(instance.root, parsed, fieldPath{}.key("value"), "value", reflect.Value{}))
+}
+
+// ValidateRequest returns a handler that decodes the JSON request body into a new value of elem's type
+// and validates it. It responds with 422 Unprocessable Entity when validation fails,
+// otherwise it calls next with the request body left readable and the decoded value in the request context,
+// which Validated returns.
+// It responds with 400 Bad Request when the body is not valid JSON or does not decode into elem's type.
+// The keys present in the body are recorded for the present, filled, sometimes and missing rules.
+//
+// Unlike the former ValidateRequest(next), elem only gives the type to decode into and is never populated,
+// so that the handler can serve concurrent requests: read the decoded value with Validated instead.
+func (v *Validator) ValidateRequest(elem any, next http.Handler) http.Handler {
+	elemType := reflect.TypeOf(elem)
+	if elemType == nil || elemType.Kind() != reflect.Pointer {
+		panic("validate: a pointer is expected as an argument")
+	}
+	return v.validateRequest(func() any { return reflect.New(elemType.Elem()).Interface() }, nil, false, next)
+}
+
+// ValidateMapRequest returns a handler that decodes the JSON request body into a map
+// and validates it against rules. It behaves like ValidateRequest otherwise:
+// Validated[map[string]any] returns the decoded map.
+func (v *Validator) ValidateMapRequest(rules map[string]string, next http.Handler) http.Handler {
+	return v.validateRequest(func() any { return &map[string]any{} }, rules, false, next)
+}
+
+// validateRequest validates the decoded request body, only the keys present in it when partial is set.
+func (v *Validator) validateRequest(newElem func() any, rules map[string]string, partial bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		elem := newElem()
+		present, err := presentPaths(body)
+		if err == nil {
+			err = json.Unmarshal(body, elem)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, struct {
+				Status  bool   `json:"status"`
+				Message string `json:"message"`
+			}{
+				Status:  false,
+				Message: "invalid JSON body: " + err.Error(),
+			})
+			return
+		}
+		instance := v.newValidation(r.Context(), rules)
+		instance.present = present
+		if partial {
+			instance.filter = &pathFilter{mode: filterPresent, present: instance.present}
+		}
+		err = instance.result(instance.validate(elem))
+		if errors.Is(err, ErrAborted) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			jsonRes := struct {
+				Status bool `json:"status"`
+				Errors any  `json:"errors"`
+			}{
+				Status: false,
+				Errors: err,
+			}
+			writeJSON(w, http.StatusUnprocessableEntity, jsonRes)
+			return
+		}
+		var decoded any = elem
+		if m, ok := elem.(*map[string]any); ok {
+			decoded = *m
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), validatedKey{}, decoded)))
+	})
+}
+
+type validatedKey struct{}
+
+// Validated returns the value decoded and validated by ValidateRequest, ValidatePartialRequest
+// or ValidateMapRequest, for use in their next handler. T is the type of the elem given to them,
+// e.g. Validated[*CreateUser](r), or map[string]any for ValidateMapRequest.
+// It reports false when the request holds no validated value of type T.
+func Validated[T any](r *http.Request) (T, bool) {
+	value, ok := r.Context().Value(validatedKey{}).(T)
+	return value, ok
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	resByte, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resByte)
+}
+
+func (v *Validator) newValidation(ctx context.Context, rules map[string]string, locale ...string) *validation {
+	instance := &validation{
+		ctx:       ctx,
+		validator: v,
+		locale:    v.locale,
+		rules:     rules,
+		tagErr:    &tagErrors{},
+	}
+	if locale != nil {
+		instance.locale = locale[0]
+	}
+	return instance
+}
+
+// result turns the collected errors into the error returned to the caller.
+// A malformed tag met during the validation is returned instead of the validation errors.
+func (v *validation) result(errMsgs []*FieldError) error {
+	if err := v.ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrAborted, err)
+	}
+	if v.tagErr.err != nil {
+		return v.tagErr.err
+	}
+	return newValidationErrors(errMsgs, v.validator.flatErrors)
+}
+
+func (v *validation) validate(elem any) []*FieldError {
 	elemType := reflect.TypeOf(elem)
 	elemValue := reflect.ValueOf(elem)
-	if elemType.Kind() != reflect.Pointer || elemValue.Kind() != reflect.Pointer {
+	if elemType == nil || elemType.Kind() != reflect.Pointer || elemValue.Kind() != reflect.Pointer {
 		panic("validate: a pointer is expected as an argument")
 	}
 	v.elem = elem
 	v.elemType = elemType.Elem()
 	v.elemValue = elemValue.Elem()
-	if locale != nil {
-		v.locale = locale[0]
+	if !v.root.IsValid() {
+		v.root = v.elemValue
 	}
 	switch v.elemType.Kind() {
 	case reflect.Struct:
@@ -78,822 +284,476 @@ func (v *validation) Validate(elem any, locale ...string) map[string]any {
 	panic("validate: a struct or map pointer is expected as an argument")
 }
 
-func (v *validation) ValidateRequest(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer r.Body.Close()
-		body, _ := io.ReadAll(r.Body)
-		json.Unmarshal(body, v.elem)
-		switch v.elemType.Kind() {
-		case reflect.Struct:
-			message := v.structValidator()
-			if len(message) > 0 {
-				v.jsonRes.Errors = message
-			} else {
-				v.jsonRes.Errors = nil
-			}
-		case reflect.Map:
-			message := v.mapValidator()
-			if len(message) > 0 {
-				v.jsonRes.Errors = message
-			} else {
-				v.jsonRes.Errors = nil
-			}
+// nested validates a nested struct pointer and reports its errors under path.
+func (v *validation) nested(elem any, path fieldPath) []*FieldError {
+	child := v.validator.newValidation(v.ctx, nil, v.locale)
+	child.prefix = path
+	child.root = v.root
+	child.filter = v.filter
+	child.present = v.present
+	child.tagErr = v.tagErr
+	return child.validate(elem)
+}
+
+func (v *validation) structValidator() []*FieldError {
+	plan, err := v.validator.loadPlan(v.elemType)
+	if err != nil {
+		v.tagErr.set(err)
+		return nil
+	}
+	keys := make([]string, 0, len(plan.fields))
+	fields := make([]*fieldPlan, 0, len(plan.fields))
+	rules := make([][]*parsedRule, 0, len(plan.fields))
+	for _, field := range plan.fields {
+		validate, descend := v.filter.check(v.prefix.key(field.name))
+		if !descend {
+			continue
 		}
-		if v.jsonRes.Errors != nil {
-			v.jsonRes.Status = false
-			resByte, _ := json.Marshal(v.jsonRes)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnprocessableEntity)
-			w.Write(resByte)
+		keys = append(keys, v.prefix.key(field.name).String())
+		fields = append(fields, field)
+		if validate {
+			rules = append(rules, field.rules)
+		} else {
+			rules = append(rules, nil)
 		}
-		next.ServeHTTP(w, r)
+	}
+	errMsgs := v.collect(keys, func(i int, msgChan chan message, wg *sync.WaitGroup) {
+		field := fields[i]
+		v.validateField(field.value(v.elemValue), rules[i], v.prefix.key(field.name), field.label, v.elemValue, msgChan, wg)
 	})
+	if v.validator.stopOnFirstError && len(errMsgs) > 0 {
+		return errMsgs
+	}
+	return append(errMsgs, v.filter.filterErrors(v.structLevel())...)
 }
 
-func (v *validation) structValidator() map[string]any {
-	mChan := make(chan message, v.elemType.NumField())
-	wg := &sync.WaitGroup{}
-	for i := 0; i < v.elemType.NumField(); i++ {
-		if _, ok := v.elemType.Field(i).Tag.Lookup("json"); ok {
-			if _, ok := v.elemType.Field(i).Tag.Lookup("validate"); ok {
-				wg.Add(1)
-				go v.validateStruct(i, mChan, wg)
+func (v *validation) mapValidator() []*FieldError {
+	keys := make([]string, 0, len(v.rules))
+	for key := range v.rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	targets := make([]mapTarget, 0, len(keys))
+	rules := make(map[string]string)
+	for _, key := range keys {
+		for _, target := range resolveMapPath(v.elemValue, v.prefix, strings.Split(key, ".")) {
+			if validate, _ := v.filter.check(target.path); !validate {
+				continue
+			}
+			if rule, ok := rules[target.path.String()]; ok {
+				if rule != "" && v.rules[key] != "" {
+					rule += "|"
+				}
+				rules[target.path.String()] = rule + v.rules[key]
 				continue
 			}
+			targets = append(targets, target)
+			rules[target.path.String()] = v.rules[key]
 		}
-		panic("json or validate tag missing")
 	}
-	wg.Wait()
-	errMsg := make(map[string]any)
-	noErrMsg := make(map[string]any)
-	for i := 0; i < v.elemType.NumField(); i++ {
-		if msg, ok := <-mChan; ok {
-			if msg.V == nil {
-				noErrMsg[msg.K] = msg.V
-			}
-			errMsg[msg.K] = msg.V
+	paths := make([]string, 0, len(targets))
+	for _, target := range targets {
+		paths = append(paths, target.path.String())
+	}
+	parsed := make([][]*parsedRule, len(targets))
+	for i, path := range paths {
+		var err error
+		if parsed[i], err = compileRules(rules[path], path); err != nil {
+			v.tagErr.set(err)
+			return nil
 		}
 	}
+	return v.collect(paths, func(i int, msgChan chan message, wg *sync.WaitGroup) {
+		v.validateField(targets[i].value, parsed[i], targets[i].path, targets[i].label(v.validator.itemLabel), targets[i].parent, msgChan, wg)
+	})
+}
+
+// collect runs validate for every key and gathers the errors in key order.
+// Fields are validated concurrently unless the validator stops on the first error.
+func (v *validation) collect(keys []string, validate func(index int, msgChan chan message, wg *sync.WaitGroup)) []*FieldError {
+	mChan := make(chan message, len(keys))
+	wg := &sync.WaitGroup{}
+	for i := range keys {
+		if v.ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		if !v.validator.stopOnFirstError {
+			go validate(i, mChan, wg)
+			continue
+		}
+		validate(i, mChan, wg)
+		if msg := <-mChan; len(msg.V) > 0 {
+			return msg.V
+		}
+	}
+	wg.Wait()
 	close(mChan)
-	if len(errMsg) != len(noErrMsg) {
-		return errMsg
+	messages := make(map[string][]*FieldError, len(keys))
+	for msg := range mChan {
+		messages[msg.K] = msg.V
 	}
-	return nil
+	var errMsgs []*FieldError
+	for _, key := range keys {
+		errMsgs = append(errMsgs, messages[key]...)
+	}
+	return errMsgs
 }
 
-func (v *validation) mapValidator() map[string]any {
-	panic("implement me")
+func (v *validation) validateField(value reflect.Value, rules []*parsedRule, path fieldPath, formattedField string, parent reflect.Value, msgChan chan message, wg *sync.WaitGroup) {
+	defer wg.Done()
+	v.sendMessages(path, v.validateValue(value, rules, path, formattedField, parent), msgChan)
 }
 
-func (v *validation) validateStruct(index int, msgChan chan message, wg *sync.WaitGroup) {
-	defer wg.Done()
-	ruleOrMsgs := strings.Split(v.elemType.Field(index).Tag.Get("validate"), "|")
-	value := v.elemValue.Field(index)
-	formattedField := formatFieldName(v.elemType.Field(index).Tag.Get("json"))
-	jsonTag := v.elemType.Field(index).Tag.Get("json")
-
-	for _, ruleOrMsg := range ruleOrMsgs {
-		rule, customMsg := getRuleAndMsg(ruleOrMsg)
-		if rule == "required" && isEmpty(value) {
-			if value.Kind() == reflect.Bool {
-				v.setMessage("bool", customMsg, jsonTag, formattedField, msgChan)
-				return
+// validateValue applies rules to value and stops at the first failing rule.
+// The rules of a collection holding dive apply to the collection up to dive and to every item after it.
+// Without dive, slice rules apply to every item unless implicit or prefixed with slice:, which is deprecated.
+// Nested structs are validated recursively.
+// Values marked omitempty are skipped when empty, values marked nullable when nil and values marked sometimes when missing.
+func (v *validation) validateValue(value reflect.Value, rules []*parsedRule, path fieldPath, field string, parent reflect.Value) []*FieldError {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if (hasRule(rules, "omitempty") && isEmpty(indirect(value))) || (hasRule(rules, "nullable") && isNil(value)) || (hasRule(rules, "sometimes") && !v.isPresent(value, path)) {
+		return nil
+	}
+	bail := v.bail(rules)
+	if value.Kind() == reflect.Pointer && !value.IsNil() && !structPointer(value) {
+		return v.validatePointer(value.Elem(), rules, path, field, parent, bail)
+	}
+	if value.Kind() == reflect.Map {
+		return v.validateMap(value, rules, path, field, parent, bail)
+	}
+	if collectionRules, itemRules, ok := splitDiveRules(rules); ok && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) {
+		return v.validateDive(value, collectionRules, itemRules, path, field, parent, bail)
+	}
+	collection := (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && !isEmpty(value)
+	itemRules := make([]*parsedRule, 0, len(rules))
+	errMsgs := make([]*FieldError, 0)
+	for _, rule := range rules {
+		if rule.def == nil {
+			continue
+		}
+		if collection && !rule.slice && !rule.def.implicit {
+			itemRules = append(itemRules, rule)
+			continue
+		}
+		if fieldErr := v.applyRule(value, rule, &RuleContext{validation: v, parent: parent, path: path}, field); fieldErr != nil {
+			errMsgs = append(errMsgs, fieldErr)
+			if bail {
+				return errMsgs
 			}
-			v.setMessage("required", customMsg, jsonTag, formattedField, msgChan)
-			return
 		}
-		if !isEmpty(value) {
-			switch value.Kind() {
-			case reflect.String:
-				switch rule {
-				case "string":
-					if isNotString(value) {
-						v.setMessage("string", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "ascii":
-					if isNotASCII(value) {
-						v.setMessage("string", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "alpha":
-					if isNotAlpha(value) {
-						v.setMessage("alpha", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "numeric":
-					if isNotNumeric(value) {
-						v.setMessage("numeric", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "alpha_numeric":
-					if isNotAlphanumeric(value) {
-						v.setMessage("alpha_numeric", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "email":
-					if isNotEmail(value) {
-						v.setMessage("email", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "phone":
-					if isNotPhone(value) {
-						v.setMessage("phone", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "phone_with_code":
-					if isNotPhoneWithCode(value) {
-						v.setMessage("phone_with_code", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "username":
-					if isNotUsername(value) {
-						v.setMessage("username", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "gh_card":
-					if isNotGHCard(value) {
-						v.setMessage("gh_card", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "gh_gps":
-					if isNotGHGPS(value) {
-						v.setMessage("gh_gps", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				default:
-					if strings.Contains(rule, ":") {
-						rSlice := strings.SplitN(rule, ":", 2)
-						switch rSlice[0] {
-						case "min":
-							if isNotMin(value, rSlice[1]) {
-								v.setMessage("min.string", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "max":
-							if isNotMax(value, rSlice[1]) {
-								v.setMessage("max.string", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "equal":
-							if isNotEqual(value, rSlice[1]) {
-								v.setMessage("equal.string", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "size":
-							if isNotSize(value, rSlice[1]) {
-								v.setMessage("size.string", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "from":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotFrom(value, minMax[0], minMax[1]) {
-								v.setMessage("from.string", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "between":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotBetween(value, minMax[0], minMax[1]) {
-								v.setMessage("between.string", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "same":
-							tag, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("same", customMsg, jsonTag, formattedField, msgChan, tag)
-								return
-							}
-						case "match":
-							_, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("match", customMsg, jsonTag, formattedField, msgChan)
-								return
-							}
-						case "unique":
-							if tc := strings.SplitN(rSlice[1], ".", 2); len(tc) == 2 {
-								if isNotUnique(v.dbConfig, value.String(), tc[1], tc[0]) {
-									v.setMessage("unique", customMsg, jsonTag, formattedField, msgChan)
-									return
-								}
-							}
-						}
+	}
+	if len(itemRules) > 0 {
+		for i := 0; i < value.Len(); i++ {
+			item := indirect(value.Index(i))
+			ctx := &RuleContext{validation: v, parent: parent, path: path.index(i)}
+			for _, rule := range itemRules {
+				if fieldErr := v.applyRule(item, rule, ctx, fmt.Sprintf(v.validator.itemLabel, field, i+1)); fieldErr != nil {
+					errMsgs = append(errMsgs, fieldErr)
+					if bail {
+						break
 					}
 				}
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				switch rule {
-				case "int":
-					if isNotInt(value) {
-						v.setMessage("int", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				case "uint":
-					if isNotUint(value) {
-						v.setMessage("uint", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				default:
-					if strings.Contains(rule, ":") {
-						rSlice := strings.SplitN(rule, ":", 2)
-						switch rSlice[0] {
-						case "min":
-							if isNotMin(value, rSlice[1]) {
-								v.setMessage("min.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "max":
-							if isNotMax(value, rSlice[1]) {
-								v.setMessage("max.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "equal":
-							if isNotEqual(value, rSlice[1]) {
-								v.setMessage("equal.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "size":
-							if isNotSize(value, rSlice[1]) {
-								v.setMessage("size.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "from":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotFrom(value, minMax[0], minMax[1]) {
-								v.setMessage("from.numeric", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "between":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotBetween(value, minMax[0], minMax[1]) {
-								v.setMessage("between.numeric", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "same":
-							tag, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("same", customMsg, jsonTag, formattedField, msgChan, tag)
-								return
-							}
-						case "match":
-							_, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("match", customMsg, jsonTag, formattedField, msgChan)
-								return
-							}
-						}
+			}
+		}
+		if bail && len(errMsgs) > 0 {
+			return errMsgs
+		}
+	}
+	return append(errMsgs, v.validateNested(value, path)...)
+}
 
-					}
-				}
-			case reflect.Float32, reflect.Float64:
-				switch rule {
-				case "float":
-					if isNotFloat(value) {
-						v.setMessage("float", customMsg, jsonTag, formattedField, msgChan)
-						return
-					}
-				default:
-					if strings.Contains(rule, ":") {
-						rSlice := strings.SplitN(rule, ":", 2)
-						switch rSlice[0] {
-						case "min":
-							if isNotMin(value, rSlice[1]) {
-								v.setMessage("min.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "max":
-							if isNotMax(value, rSlice[1]) {
-								v.setMessage("max.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "equal":
-							if isNotEqual(value, rSlice[1]) {
-								v.setMessage("equal.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "size":
-							if isNotSize(value, rSlice[1]) {
-								v.setMessage("size.numeric", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-								return
-							}
-						case "from":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotFrom(value, minMax[0], minMax[1]) {
-								v.setMessage("from.numeric", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "between":
-							minMax := strings.SplitN(rSlice[1], ",", 2)
-							if isNotBetween(value, minMax[0], minMax[1]) {
-								v.setMessage("between.numeric", customMsg, jsonTag, formattedField, msgChan, minMax[0], minMax[1])
-								return
-							}
-						case "same":
-							tag, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("same", customMsg, jsonTag, formattedField, msgChan, tag)
-								return
-							}
-						case "match":
-							_, val := v.getTagAndValue(rSlice[1])
-							if isNotSame(value, val) {
-								v.setMessage("match", customMsg, jsonTag, formattedField, msgChan)
-								return
-							}
-						}
+// validateMap applies the rules preceding keys or values to the map, the rules between keys and endkeys
+// to every key and the rules following values to every value. The errors of an entry are reported under its key.
+func (v *validation) validateMap(value reflect.Value, rules []*parsedRule, path fieldPath, field string, parent reflect.Value, bail bool) []*FieldError {
+	mapRules, keyRules, valueRules := splitMapRules(rules)
+	errMsgs := v.applyRules(value, mapRules, &RuleContext{validation: v, parent: parent, path: path}, field, bail)
+	if bail && len(errMsgs) > 0 {
+		return errMsgs
+	}
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	for _, key := range keys {
+		entryPath := path.key(fmt.Sprint(key))
+		label := fmt.Sprintf("%s (%v)", field, key)
+		if keyErrs := v.validateValue(key, keyRules, entryPath, label, value); len(keyErrs) > 0 {
+			errMsgs = append(errMsgs, keyErrs...)
+			continue
+		}
+		errMsgs = append(errMsgs, v.validateValue(value.MapIndex(key), valueRules, entryPath, label, value)...)
+	}
+	return errMsgs
+}
 
-					}
-				}
-			case reflect.Slice, reflect.Array:
-				if strings.HasPrefix(rule, "slice") && strings.Contains(rule, ":") {
-					rSlice := strings.SplitN(rule, ":", 3)[1:]
-					switch rSlice[0] {
-					case "min":
-						if isNotMin(value, rSlice[0]) {
-							v.setMessage("min.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-							return
-						}
-					case "max":
-						if isNotMax(value, rSlice[0]) {
-							v.setMessage("max.slice", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-							return
-						}
-					}
-				}
-				switch value.Type().Elem().Kind() {
-				case reflect.String:
-					errMsgs := make([]any, 0, value.Len())
-					for i := 1; i <= value.Len(); i++ {
-						value := value.Index(i - 1)
-						switch rule {
-						case "string":
-							if isNotString(value) {
-								errMsgs = append(errMsgs, v.generateMessage("string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "ascii":
-							if isNotASCII(value) {
-								errMsgs = append(errMsgs, v.generateMessage("string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "alpha":
-							if isNotAlpha(value) {
-								errMsgs = append(errMsgs, v.generateMessage("alpha", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "numeric":
-							if isNotNumeric(value) {
-								errMsgs = append(errMsgs, v.generateMessage("numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "alpha_numeric":
-							if isNotAlphanumeric(value) {
-								errMsgs = append(errMsgs, v.generateMessage("alpha_numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "email":
-							if isNotEmail(value) {
-								errMsgs = append(errMsgs, v.generateMessage("email", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "phone":
-							if isNotPhone(value) {
-								errMsgs = append(errMsgs, v.generateMessage("phone", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "phone_with_code":
-							if isNotPhoneWithCode(value) {
-								errMsgs = append(errMsgs, v.generateMessage("phone_with_code", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "username":
-							if isNotUsername(value) {
-								errMsgs = append(errMsgs, v.generateMessage("username", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "gh_card":
-							if isNotGHCard(value) {
-								errMsgs = append(errMsgs, v.generateMessage("gh_card", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "gh_gps":
-							if isNotGHGPS(value) {
-								errMsgs = append(errMsgs, v.generateMessage("gh_gps", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						default:
-							if strings.Contains(rule, ":") {
-								rSlice := strings.SplitN(rule, ":", 2)
-								switch rSlice[0] {
-								case "min":
-									if isNotMin(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("min.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "max":
-									if isNotMax(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("max.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "equal":
-									if isNotEqual(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("equal.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "size":
-									if isNotSize(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("size.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "from":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotFrom(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("from.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "between":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotBetween(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("between.string", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "same":
-									tag, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("same", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), tag))
-										continue
-									}
-								case "match":
-									_, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("match", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-										continue
-									}
-								case "unique":
-									if tc := strings.SplitN(rSlice[1], ".", 2); len(tc) == 2 {
-										if isNotUnique(v.dbConfig, value.String(), tc[1], tc[0]) {
-											errMsgs = append(errMsgs, v.generateMessage("unique", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-											continue
-										}
-									}
-								}
-							}
-						}
-					}
-					if len(errMsgs) > 0 {
-						v.setMessage("", errMsgs, jsonTag, formattedField, msgChan)
-						return
-					}
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-					errMsgs := make([]any, 0, value.Len())
-					for i := 1; i <= value.Len(); i++ {
-						switch rule {
-						case "int":
-							if isNotInt(value) {
-								errMsgs = append(errMsgs, v.generateMessage("int", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						case "uint":
-							if isNotUint(value) {
-								errMsgs = append(errMsgs, v.generateMessage("uint", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						default:
-							if strings.Contains(rule, ":") {
-								rSlice := strings.SplitN(rule, ":", 2)
-								switch rSlice[0] {
-								case "min":
-									if isNotMin(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("min.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "max":
-									if isNotMax(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("max.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "equal":
-									if isNotEqual(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("equal.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "from":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotFrom(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("from.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "between":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotBetween(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("between.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "same":
-									tag, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("same", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), tag))
-										continue
-									}
-								case "match":
-									_, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("match", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-										continue
-									}
-								}
-							}
-						}
-					}
-					if len(errMsgs) > 0 {
-						v.setMessage("", errMsgs, jsonTag, formattedField, msgChan)
-						return
-					}
-				case reflect.Float32, reflect.Float64:
-					errMsgs := make([]any, 0, value.Len())
-					for i := 1; i <= value.Len(); i++ {
-						switch rule {
-						case "float":
-							if isNotFloat(value) {
-								errMsgs = append(errMsgs, v.generateMessage("float", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-								continue
-							}
-						default:
-							if strings.Contains(rule, ":") {
-								rSlice := strings.SplitN(rule, ":", 2)
-								switch rSlice[0] {
-								case "min":
-									if isNotMin(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("min.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "max":
-									if isNotMax(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("max.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "equal":
-									if isNotEqual(value, rSlice[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("equal.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-										continue
-									}
-								case "from":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotFrom(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("from.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "between":
-									minMax := strings.SplitN(rSlice[1], ",", 2)
-									if isNotBetween(value, minMax[0], minMax[1]) {
-										errMsgs = append(errMsgs, v.generateMessage("between.numeric", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), minMax[0], minMax[1]))
-										continue
-									}
-								case "same":
-									tag, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("same", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), tag))
-										continue
-									}
-								case "match":
-									_, val := v.getTagAndValue(rSlice[1])
-									if isNotSame(value, val) {
-										errMsgs = append(errMsgs, v.generateMessage("match", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-										continue
-									}
-								}
-							}
-						}
-					}
-					if len(errMsgs) > 0 {
-						v.setMessage("", errMsgs, jsonTag, formattedField, msgChan)
-						return
-					}
-				case reflect.Pointer, reflect.Interface:
-					if _, ok := value.Interface().([]*multipart.FileHeader); ok {
-						errMsgs := make([]any, 0, value.Len())
-						for i := 1; i <= value.Len(); i++ {
-							value := value.Index(i - 1)
-							switch rule {
-							case "image":
-								if isNotMimes(value, "jpg,jpeg,png,webp") {
-									errMsgs = append(errMsgs, v.generateMessage("image", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-									continue
-								}
-							case "file":
-								if isNotFile(value) {
-									errMsgs = append(errMsgs, v.generateMessage("file", customMsg, fmt.Sprintf("%s (%d)", formattedField, i)))
-									continue
-								}
-							default:
-								if strings.Contains(rule, ":") {
-									rSlice := strings.SplitN(rule, ":", 2)
-									switch rSlice[0] {
-									case "image":
-										if isNotMimes(value, rSlice[1]) {
-											errMsgs = append(errMsgs, v.generateMessage("image_type", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-											continue
-										}
-									case "file":
-										if isNotMimes(value, rSlice[1]) {
-											errMsgs = append(errMsgs, v.generateMessage("file_type", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-											continue
-										}
-									case "mimes":
-										if isNotMimes(value, rSlice[1]) {
-											errMsgs = append(errMsgs, v.generateMessage("mimes", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), rSlice[1]))
-											continue
-										}
-									case "size":
-										rgx := regexp.MustCompile(`^([1-9]|[1-9][0-9]+)(kb|KB|mb|MB|gb|GB|tb|TB)$`)
-										matches := rgx.FindAllStringSubmatch(rSlice[1], -1)
-										size, symbol := matches[0][1], matches[0][2]
-										size64, _ := strconv.ParseInt(size, 10, 64)
-										if fh, ok := value.Interface().(*multipart.FileHeader); ok {
-											switch strings.ToLower(symbol) {
-											case "kb":
-												if fh.Size > int64(kilobyte*size64) {
-													errMsgs = append(errMsgs, v.generateMessage("size.file_kb", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), size))
-													continue
-												}
-											case "mb":
-												if fh.Size > int64(megabyte*size64) {
-													errMsgs = append(errMsgs, v.generateMessage("size.file_mb", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), size))
-													continue
-												}
-											case "gb":
-												if fh.Size > int64(gigabyte*size64) {
-													errMsgs = append(errMsgs, v.generateMessage("size.file_gb", customMsg, fmt.Sprintf("%s (%d)", formattedField, i), size))
-													continue
-												}
-											}
-										}
-
-									}
-								}
-							}
-						}
-						if len(errMsgs) > 0 {
-							v.setMessage("", errMsgs, jsonTag, formattedField, msgChan)
-							return
-						}
-					} else {
-						err := make(map[string]any)
-						for i := 0; i < value.Len(); i++ {
-							if msg := New(v.dbConfig).Validate(value.Index(i).Interface(), v.locale); msg != nil {
-								err[fmt.Sprintf("%s.%d", jsonTag, i)] = msg
-							}
-						}
-						if len(err) > 0 {
-							v.setMessage("", err, jsonTag, formattedField, msgChan)
-							return
-						}
-					}
-				}
-			case reflect.Pointer, reflect.Interface:
-				if _, ok := value.Interface().(*multipart.FileHeader); ok {
-					switch rule {
-					case "image":
-						if isNotMimes(value, "jpg,jpeg,png,webp") {
-							v.setMessage("image", customMsg, jsonTag, formattedField, msgChan)
-							return
-						}
-					case "file":
-						if isNotFile(value) {
-							v.setMessage("file", customMsg, jsonTag, formattedField, msgChan)
-							return
-						}
-					default:
-						if strings.Contains(rule, ":") {
-							rSlice := strings.SplitN(rule, ":", 2)
-							switch rSlice[0] {
-							case "image":
-								if isNotMimes(value, rSlice[1]) {
-									v.setMessage("image_type", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-									return
-								}
-							case "file":
-								if isNotMimes(value, rSlice[1]) {
-									v.setMessage("file_type", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-									return
-								}
-							case "mimes":
-								if isNotMimes(value, rSlice[1]) {
-									v.setMessage("mimes", customMsg, jsonTag, formattedField, msgChan, rSlice[1])
-									return
-								}
-							case "size":
-								rgx := regexp.MustCompile(`^([1-9]|[1-9][0-9]+)(kb|KB|mb|MB|gb|GB|tb|TB)$`)
-								matches := rgx.FindAllStringSubmatch(rSlice[1], -1)
-								size, symbol := matches[0][1], matches[0][2]
-								size64, _ := strconv.ParseInt(size, 10, 64)
-								fh := value.Interface().(*multipart.FileHeader)
-								switch strings.ToLower(symbol) {
-								case "kb":
-									if fh.Size > int64(kilobyte*size64) {
-										v.setMessage("size.file_kb", customMsg, jsonTag, formattedField, msgChan, size)
-										return
-									}
-								case "mb":
-									if fh.Size > int64(megabyte*size64) {
-										v.setMessage("size.file_mb", customMsg, jsonTag, formattedField, msgChan, size)
-										return
-									}
-								case "gb":
-									if fh.Size > int64(gigabyte*size64) {
-										v.setMessage("size.file_gb", customMsg, jsonTag, formattedField, msgChan, size)
-										return
-									}
-								}
-
-							}
-						}
-					}
-				} else {
-					if msg := New(v.dbConfig).Validate(value.Interface(), v.locale); msg != nil {
-						v.setMessage("", msg, jsonTag, formattedField, msgChan)
-						return
-					}
-				}
+// validatePointer validates the value pointed to by a non-nil pointer. The rules apply to a zero value too,
+// since only a nil pointer is empty: a pointer to false satisfies required.
+func (v *validation) validatePointer(value reflect.Value, rules []*parsedRule, path fieldPath, field string, parent reflect.Value, bail bool) []*FieldError {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return v.validateValue(value, rules, path, field, parent)
+	}
+	return v.applyRules(value, rules, &RuleContext{validation: v, parent: parent, path: path, present: true}, field, bail)
+}
+
+// validateDive applies rules to the slice or array value and itemRules to each of its items.
+func (v *validation) validateDive(value reflect.Value, rules, itemRules []*parsedRule, path fieldPath, field string, parent reflect.Value, bail bool) []*FieldError {
+	errMsgs := v.applyRules(value, rules, &RuleContext{validation: v, parent: parent, path: path}, field, bail)
+	if bail && len(errMsgs) > 0 {
+		return errMsgs
+	}
+	for i := 0; i < value.Len(); i++ {
+		errMsgs = append(errMsgs, v.validateValue(value.Index(i), itemRules, path.index(i), fmt.Sprintf(v.validator.itemLabel, field, i+1), parent)...)
+	}
+	return errMsgs
+}
+
+// applyRules applies rules to value, stopping at the first failing rule when bail is set.
+func (v *validation) applyRules(value reflect.Value, rules []*parsedRule, ctx *RuleContext, field string, bail bool) []*FieldError {
+	errMsgs := make([]*FieldError, 0)
+	for _, rule := range rules {
+		if fieldErr := v.applyRule(value, rule, ctx, field); fieldErr != nil {
+			errMsgs = append(errMsgs, fieldErr)
+			if bail {
+				break
 			}
+		}
+	}
+	return errMsgs
+}
 
+// splitDiveRules splits rules on their first dive, if any.
+func splitDiveRules(rules []*parsedRule) (collectionRules, itemRules []*parsedRule, ok bool) {
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			return rules[:i], rules[i+1:], true
 		}
 	}
-	v.setMessage("empty", "", jsonTag, formattedField, msgChan)
+	return rules, nil, false
 }
 
-func (v *validation) getTagAndValue(lookupTag string) (tag string, value reflect.Value) {
-	for i := 0; i < v.elemType.NumField(); i++ {
-		if t, ok := v.elemType.Field(i).Tag.Lookup("json"); ok {
-			if t == lookupTag {
-				tag = t
-				value = v.elemValue.Field(i)
-				return
-			}
+// splitMapRules splits the rules of a map field on the keys, endkeys and values markers.
+// A dive following the map rules behaves like values. The rules following values are kept as is,
+// so that nested collections can use their own markers.
+func splitMapRules(rules []*parsedRule) (mapRules, keyRules, valueRules []*parsedRule) {
+	target := &mapRules
+	for i, rule := range rules {
+		switch {
+		case rule.name == "keys" && target == &mapRules:
+			target = &keyRules
+		case rule.name == "endkeys" && target == &keyRules:
+			target = &mapRules
+		case (rule.name == "values" || rule.name == "dive") && target == &mapRules:
+			return mapRules, keyRules, rules[i+1:]
+		default:
+			*target = append(*target, rule)
 		}
 	}
 	return
 }
 
-func (v *validation) setMessage(ruleKey string, customMsg any, msgKey, field string, msgChan chan message, values ...string) {
-	if ruleKey == "empty" {
-		msgChan <- message{
-			K: msgKey,
-			V: customMsg,
+// isPresent reports whether the value at path was provided in the input.
+// It uses the keys of the decoded request body when known, the keys of the validated map otherwise.
+// Without them an empty value is missing: a nil pointer, interface, map or slice, or a zero value.
+// A non-nil pointer is present, even to a zero value.
+func (v *validation) isPresent(value reflect.Value, path fieldPath) bool {
+	if v.present != nil {
+		return v.present[strings.ToLower(path.String())]
+	}
+	if v.root.Kind() == reflect.Map {
+		return hasMapPath(v.root, path)
+	}
+	return !isEmpty(value)
+}
+
+// bail reports whether the validation of a field stops at its first failing rule.
+// It always does unless the validator collects all errors, in which case only fields with the bail rule do.
+func (v *validation) bail(rules []*parsedRule) bool {
+	return !v.validator.collectAllErrors || hasRule(rules, "bail")
+}
+
+// hasRule reports whether rules hold the rule name, such as the bail, nullable and omitempty markers.
+func hasRule(rules []*parsedRule, name string) bool {
+	for _, rule := range rules {
+		if rule.name == name {
+			return true
 		}
-	} else if customMsg != "" {
-		msgChan <- message{
-			K: msgKey,
-			V: customMsg,
+	}
+	return false
+}
+
+func (v *validation) applyRule(value reflect.Value, rule *parsedRule, ctx *RuleContext, field string) *FieldError {
+	def := rule.def
+	if def == nil || (!def.implicit && !ctx.present && isEmpty(value)) || !def.accepts(value) || v.ctx.Err() != nil {
+		return nil
+	}
+	fn := def.fn
+	if rule.fn != nil {
+		fn = rule.fn
+	}
+	if fn(value, rule.params, ctx) {
+		return nil
+	}
+	key, args := def.key, rule.params
+	if key == "" {
+		key = rule.name
+	}
+	if def.message != nil {
+		key, args = def.message(value, rule.params, ctx)
+	}
+	fieldErr := &FieldError{
+		Field:   ctx.path.String(),
+		Key:     ctx.path.jsonKey(),
+		Rule:    rule.name,
+		Params:  rule.params,
+		Message: rule.customMsg,
+		Locale:  v.getLocale(),
+		path:    ctx.path,
+	}
+	if fieldErr.Message == "" {
+		fieldErr.Message = formatMessage(v.getMessage(key, value), field, args)
+	}
+	return fieldErr
+}
+
+// validateNested validates the struct pointers held by value, or by the items of value.
+func (v *validation) validateNested(value reflect.Value, path fieldPath) []*FieldError {
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		errMsgs := make([]*FieldError, 0)
+		for i := 0; i < value.Len(); i++ {
+			if elem, ok := nestedStruct(value.Index(i)); ok {
+				errMsgs = append(errMsgs, v.nested(elem, path.index(i))...)
+			}
 		}
-	} else {
-		if values != nil {
-			if len(values) > 1 {
-				msgChan <- message{
-					K: msgKey,
-					V: fmt.Sprintf(v.getMessage(ruleKey), field, values[0], values[1]),
-				}
-			} else {
-				msgChan <- message{
-					K: msgKey,
-					V: fmt.Sprintf(v.getMessage(ruleKey), field, values[0]),
-				}
+		return errMsgs
+	}
+	if elem, ok := nestedStruct(value); ok {
+		return v.nested(elem, path)
+	}
+	return nil
+}
+
+// lookupPath returns the value found by following keys from value.
+// Keys are JSON keys of struct fields, slice indexes or map keys.
+func (v *validation) lookupPath(value reflect.Value, keys []string) reflect.Value {
+	for _, key := range keys {
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}
 			}
-		} else {
-			msgChan <- message{
-				K: msgKey,
-				V: fmt.Sprintf(v.getMessage(ruleKey), field),
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			plan, err := v.validator.loadPlan(value.Type())
+			if err != nil {
+				return reflect.Value{}
+			}
+			field, ok := plan.byName[key]
+			if !ok {
+				return reflect.Value{}
+			}
+			value = field.value(value)
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}
+			}
+			value = value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key()))
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= value.Len() {
+				return reflect.Value{}
 			}
+			value = value.Index(i)
+		default:
+			return reflect.Value{}
 		}
 	}
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	return value
 }
 
-func (v *validation) generateMessage(ruleKey string, customMsg any, field string, values ...string) any {
-	if ruleKey == "empty" {
-		return ""
-	} else if customMsg != "" {
-		return customMsg
-	} else {
-		if values != nil {
-			if len(values) > 1 {
-				return fmt.Sprintf(v.getMessage(ruleKey), field, values[0], values[1])
-			}
-			return fmt.Sprintf(v.getMessage(ruleKey), field, values[0])
+// reference returns the field referenced by a rule parameter of the field at path:
+// name or a.b for a field of the current struct or map, ../name for a field of the parent struct or map,
+// which may repeat as in ../../name, and $.a.b for a field from the root.
+// In Var mode it returns the other value.
+func (v *validation) reference(ref string, path fieldPath) reflect.Value {
+	if v.elemType == nil {
+		return v.other
+	}
+	if strings.HasPrefix(ref, "$.") {
+		return v.lookupPath(v.root, strings.Split(ref[2:], "."))
+	}
+	base, relative := v.prefix, strings.HasPrefix(ref, "../")
+	if v.elemType.Kind() == reflect.Map && len(path) > 0 {
+		base = path[:len(path)-1]
+	}
+	for strings.HasPrefix(ref, "../") {
+		ref = ref[3:]
+		for len(base) > 0 && base[len(base)-1].index >= 0 {
+			base = base[:len(base)-1]
+		}
+		if len(base) > 0 {
+			base = base[:len(base)-1]
 		}
-		return fmt.Sprintf(v.getMessage(ruleKey), field)
 	}
+	keys := make([]string, 0, len(base)+1)
+	for _, seg := range base {
+		keys = append(keys, seg.key)
+	}
+	value := v.lookupPath(v.root, append(keys, strings.Split(ref, ".")...))
+	if !value.IsValid() && !relative && v.elemType.Kind() == reflect.Map {
+		// Map rules used to reference fields from the root of the map.
+		value = v.lookupPath(v.root, strings.Split(ref, "."))
+	}
+	return value
+}
+
+func (v *validation) sendMessages(path fieldPath, errMsgs []*FieldError, msgChan chan message) {
+	msgChan <- message{
+		K: path.String(),
+		V: errMsgs,
+	}
+}
+
+func (v *validation) getLocale() string {
+	if v.locale == "" {
+		return LocaleEN
+	}
+	return strings.ToLower(v.locale)
 }
 
-func (v *validation) getMessage(rule string) string {
+func (v *validation) getMessage(key string, value reflect.Value) string {
+	if msg, ok := registeredMessage(v.getLocale(), key); ok {
+		return msg
+	}
 	var message map[string]any
-	switch strings.ToLower(v.locale) {
-	case "fr":
+	switch v.getLocale() {
+	case LocaleFR:
 		message = locale.FR
 	default:
 		message = locale.EN
 	}
-	if strings.Contains(rule, ".") {
-		keys := strings.SplitN(rule, ".", 2)
-		msg := message[keys[0]].(map[string]string)
-		return msg[keys[1]]
+	group := kindGroup(value)
+	if strings.Contains(key, ".") {
+		keys := strings.SplitN(key, ".", 2)
+		key, group = keys[0], keys[1]
+	}
+	switch msg := message[key].(type) {
+	case string:
+		return msg
+	case map[string]string:
+		if groupMsg, ok := msg[group]; ok {
+			return groupMsg
+		}
+	}
+	if msg, ok := registeredMessage(LocaleEN, key); ok {
+		return msg
 	}
-	return message[rule].(string)
+	return message["invalid"].(string)
 }
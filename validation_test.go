@@ -1,9 +1,19 @@
 package validata
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type TestDeepStruct struct {
@@ -58,4 +68,620 @@ func TestMain(m *testing.M) {
 	}
 
 	myLogger.Println(New().Validate(request))
+	os.Exit(m.Run())
+}
+
+func TestValidateMap(t *testing.T) {
+	payload := map[string]any{
+		"name":  "",
+		"email": "contact@mail.com",
+		"address": map[string]any{
+			"city": "",
+		},
+		"tags": []any{"go", "a-b"},
+		"contacts": []any{
+			map[string]any{"email": "contact@mail.com"},
+			map[string]any{"email": "contact@example.com"},
+		},
+	}
+	rules := map[string]string{
+		"name":             "required",
+		"email":            "required|email",
+		"address.city":     "required",
+		"tags.*":           "alpha",
+		"contacts.*.email": "required|email",
+		"missing":          "required>Missing is required",
+	}
+	err := New().ValidateMap(payload, rules)
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := map[string]string{
+		"address.city":     "The city field is required.",
+		"contacts.1.email": "The email must be a valid email address.",
+		"missing":          "Missing is required",
+		"name":             "The name field is required.",
+		"tags.1":           "The tags (2) may only contain letters.",
+	}
+	if fields := errs.Fields(); len(fields) != len(expected) {
+		t.Errorf("fields: got %v", fields)
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+	if errs.Has("email") || errs.Has("contacts.0") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	body, _ := json.Marshal(errs)
+	var legacy map[string]any
+	json.Unmarshal(body, &legacy)
+	if tags, _ := legacy["tags"].([]any); len(tags) != 1 || tags[0] != expected["tags.1"] {
+		t.Errorf("unexpected legacy layout: %s", body)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	request := &TestStruct{
+		Name:     "Seyram",
+		Phone:    "0241234567",
+		Username: "0241234567",
+		Terms:    true,
+		Items:    []string{"abc"},
+		Contacts: []*TestDeepStruct{
+			{Name: "Wood", Email: "contact@mail.com"},
+			{Name: "Wood", Email: "contact@example.com"},
+		},
+	}
+	err := New().Validate(request)
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if !errs.Has("contacts") || !errs.Has("contacts.1.email") || errs.Has("contacts.0") {
+		t.Errorf("unexpected fields: %v", errs.Fields())
+	}
+	fieldErr := errs.All()[0]
+	if fieldErr.Field != "name" || fieldErr.Key != "name" || fieldErr.Rule != "from" || fieldErr.Locale != LocaleEN {
+		t.Errorf("unexpected field error: %+v", fieldErr)
+	}
+	if len(fieldErr.Params) != 2 || fieldErr.Params[0] != "1" || fieldErr.Params[1] != "5" {
+		t.Errorf("unexpected params: %v", fieldErr.Params)
+	}
+	body, _ := json.Marshal(errs)
+	var legacy map[string]any
+	json.Unmarshal(body, &legacy)
+	contacts, _ := legacy["contacts"].(map[string]any)
+	contact, _ := contacts["contacts.1"].(map[string]any)
+	if contact["email"] != "The email must be a valid email address." {
+		t.Errorf("unexpected legacy layout: %s", body)
+	}
+}
+
+func TestValidateMapValid(t *testing.T) {
+	payload := map[string]any{"password": "secret", "password_confirmation": "secret"}
+	rules := map[string]string{"password": "required|min:6", "password_confirmation": "required|same:password"}
+	if err := New().ValidateMap(payload, rules); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+}
+
+type testMapKey string
+
+func TestValidateMapNamedKeys(t *testing.T) {
+	payload := map[string]any{"meta": map[testMapKey]any{"a": ""}}
+	expectErrors(t, New().ValidateMap(payload, map[string]string{"meta.a": "required"}), map[string]string{
+		"meta.a": "The a field is required.",
+	})
+}
+
+func TestValidateMapPointer(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "ValidateMap") {
+			t.Errorf("expected a panic pointing to ValidateMap, got %v", r)
+		}
+	}()
+	payload := map[string]any{"name": ""}
+	_ = New().Validate(&payload)
+}
+
+func TestValidatorConcurrentUse(t *testing.T) {
+	validator := New(WithLocale(LocaleFR))
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			request := &TestDeepStruct{Name: "Wood", Email: "contact@mail.com"}
+			if i%2 == 0 {
+				request.Email = ""
+			}
+			err := validator.Validate(request)
+			if i%2 == 0 && err == nil {
+				t.Errorf("%d: expected an error", i)
+			}
+			if i%2 != 0 && err != nil {
+				t.Errorf("%d: unexpected error %v", i, err)
+			}
+			if err != nil && err.(*ValidationErrors).First("email") != "Le champ email est requis." {
+				t.Errorf("%d: unexpected message %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestWithStopOnFirstError(t *testing.T) {
+	err := New(WithStopOnFirstError()).Validate(&TestDeepStruct{Phone: "0241234567"})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if fields := errs.Fields(); len(fields) != 1 || fields[0] != "name" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestWithTagName(t *testing.T) {
+	type request struct {
+		Name string `json:"name" rules:"required"`
+	}
+	if err := New(WithTagName("rules")).Validate(&request{}); err == nil || !err.(*ValidationErrors).Has("name") {
+		t.Errorf("expected name error, got %v", err)
+	}
+}
+
+func TestFieldKeys(t *testing.T) {
+	type request struct {
+		Name     string `json:"name,omitempty" validate:"required"`
+		Email    string `validate:"required"`
+		Password string `json:"-" validate:"required"`
+		Internal string `json:"internal"`
+		Skipped  string `json:"skipped" validate:"-"`
+		secret   string `validate:"required"`
+	}
+	var errs *ValidationErrors
+	if !errors.As(New().Validate(&request{secret: ""}), &errs) {
+		t.Fatal("expected *ValidationErrors")
+	}
+	if fields := strings.Join(errs.Fields(), ","); fields != "name,Email" {
+		t.Errorf("unexpected fields: %s", fields)
+	}
+
+	type form struct {
+		Name string `json:"name" form:"full_name" binding:"required"`
+	}
+	err := New(WithTagName("binding"), WithKeyTagName("form")).Validate(&form{})
+	if !errors.As(err, &errs) || errs.First("full_name") != "The full name field is required." {
+		t.Errorf("expected full_name error, got %v", err)
+	}
+}
+
+func TestWithFlatErrors(t *testing.T) {
+	request := &TestStruct{
+		Name:     "Wood",
+		Phone:    "0241234567",
+		Username: "0241234567",
+		Terms:    true,
+		Items:    []string{"abc", "abcdef"},
+		Contacts: []*TestDeepStruct{{Name: "Wood", Email: "invalid"}},
+	}
+	tests := []struct {
+		separator string
+		style     IndexStyle
+		expected  map[string]any
+	}{
+		{".", IndexDot, map[string]any{
+			"description":      "The description field is required.",
+			"items.0":          "The items #1 must be at least 6 characters.",
+			"contacts.0.email": "The email must be a valid email address.",
+		}},
+		{"/", IndexBracket, map[string]any{
+			"description":       "The description field is required.",
+			"items[0]":          "The items #1 must be at least 6 characters.",
+			"contacts[0]/email": "The email must be a valid email address.",
+		}},
+	}
+	for _, tt := range tests {
+		err := New(WithFlatErrors(tt.separator, tt.style), WithItemLabel("%s #%d")).Validate(request)
+		body, _ := json.Marshal(err)
+		expected, _ := json.Marshal(tt.expected)
+		if string(body) != string(expected) {
+			t.Errorf("got %s, want %s", body, expected)
+		}
+	}
+}
+
+type TestAudit struct {
+	CreatedBy string `json:"created_by" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+}
+
+func TestNestedStructs(t *testing.T) {
+	type request struct {
+		TestAudit
+		*TestDeepStruct `json:"owner" validate:"_"`
+		Name            string                    `json:"name" validate:"string"`
+		Contact         TestDeepStruct            `json:"contact" validate:"_"`
+		Contacts        []TestDeepStruct          `json:"contacts" validate:"required"`
+		Labels          map[string]TestDeepStruct `json:"labels" validate:"_"`
+		CreatedAt       time.Time                 `json:"created_at" validate:"required"`
+	}
+	err := New().Validate(&request{
+		Name:           "Wood",
+		TestDeepStruct: &TestDeepStruct{Name: "Wood"},
+		Contact:        TestDeepStruct{Name: "Wood", Email: "invalid"},
+		Contacts:       []TestDeepStruct{{Name: "Wood", Email: "contact@mail.com"}, {Email: "contact@mail.com"}},
+		Labels:         map[string]TestDeepStruct{"home": {Name: "Wood"}},
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := "created_by,owner.email,contact.email,contacts.1.name,labels.home.email,created_at"
+	if fields := strings.Join(errs.Fields(), ","); fields != expected {
+		t.Errorf("got fields %s, want %s", fields, expected)
+	}
+}
+
+type testBase struct {
+	Inner string `json:"inner" validate:"required"`
+}
+
+type testEntity struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func TestUnexportedEmbeddedStructs(t *testing.T) {
+	type request struct {
+		testBase
+		*testEntity
+		Name string `json:"name" validate:"required"`
+	}
+	err := New().Validate(&request{Name: "Wood", testEntity: &testEntity{}})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if fields := strings.Join(errs.Fields(), ","); fields != "inner,code" {
+		t.Errorf("expected the fields of unexported embedded structs to be promoted, got %s", fields)
+	}
+	if err := New().Validate(&request{testBase: testBase{Inner: "x"}, testEntity: &testEntity{Code: "A1"}, Name: "Wood"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMapFields(t *testing.T) {
+	type request struct {
+		Metadata map[string]string `json:"metadata" validate:"required|max:3|keys|alpha|endkeys|values|required|max:5"`
+		Scores   map[string]any    `json:"scores" validate:"values|max:10"`
+		Counts   map[int]int       `json:"counts" validate:"keys|max:5|endkeys"`
+	}
+	err := New().Validate(&request{
+		Metadata: map[string]string{"color": "red", "size": "", "x-y": "z", "note": "too long"},
+		Scores:   map[string]any{"go": 1, "rust": 11},
+		Counts:   map[int]int{1: 1, 7: 2},
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if errs.First("metadata") != "The metadata must not have more than 3 items." || errs.Has("metadata.size") {
+		t.Errorf("expected the map rules to bail first, got %v", errs)
+	}
+
+	err = New().Validate(&request{
+		Metadata: map[string]string{"color": "red", "size": "", "x-y": "z"},
+		Scores:   map[string]any{"go": 1, "rust": 11},
+		Counts:   map[int]int{1: 1, 7: 2},
+	})
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := map[string]string{
+		"metadata.size": "The metadata (size) field is required.",
+		"metadata.x-y":  "The metadata (x-y) may only contain letters.",
+		"scores.rust":   "The scores (rust) must not be greater than 10.",
+		"counts.7":      "The counts (7) must not be greater than 5.",
+	}
+	if fields := errs.Fields(); len(fields) != len(expected) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+}
+
+func TestDive(t *testing.T) {
+	word, empty := "go", ""
+	type request struct {
+		Matrix   [][]string        `json:"matrix" validate:"required|max:2|dive|required|dive|alpha"`
+		Grid     [2][2]int         `json:"grid" validate:"dive|dive|max:9"`
+		Counters []map[string]int  `json:"counters" validate:"dive|keys|alpha|endkeys|values|min:1"`
+		Words    []*string         `json:"words" validate:"min:2|dive|required|alpha"`
+		Groups   map[string][]int  `json:"groups" validate:"dive|max:1|dive|max:5"`
+		Contacts []*TestDeepStruct `json:"contacts" validate:"min:1|dive|required"`
+	}
+	err := New().Validate(&request{
+		Matrix:   [][]string{{"go", "a1"}, {}},
+		Grid:     [2][2]int{{1, 2}, {3, 10}},
+		Counters: []map[string]int{{"a": 1}, {"b2": 1, "c": -1}},
+		Words:    []*string{&word, &empty, nil},
+		Groups:   map[string][]int{"a": {1, 9}, "b": {6}},
+		Contacts: []*TestDeepStruct{nil, {Name: "Wood"}},
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	expected := map[string]string{
+		"matrix.0.1":       "The matrix (1) (2) may only contain letters.",
+		"matrix.1":         "The matrix (2) field is required.",
+		"grid.1.1":         "The grid (2) (2) must not be greater than 9.",
+		"counters.1.b2":    "The counters (2) (b2) may only contain letters.",
+		"counters.1.c":     "The counters (2) (c) must be at least 1",
+		"words.1":          "The words (2) may only contain letters.",
+		"words.2":          "The words (3) field is required.",
+		"groups.a":         "The groups (a) must not have more than 1 items.",
+		"groups.b.0":       "The groups (b) (1) must not be greater than 5.",
+		"contacts.0":       "The contacts (1) field is required.",
+		"contacts.1.email": "The email field is required.",
+	}
+	if fields := errs.Fields(); len(fields) != len(expected) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	for path, msg := range expected {
+		if got := errs.First(path); got != msg {
+			t.Errorf("%s: got %q, want %q", path, got, msg)
+		}
+	}
+	body, _ := json.Marshal(errs)
+	var legacy map[string]map[string]any
+	json.Unmarshal(body, &legacy)
+	if legacy["matrix"]["matrix.1"] != expected["matrix.1"] {
+		t.Errorf("unexpected legacy layout: %s", body)
+	}
+}
+
+func TestPointerFields(t *testing.T) {
+	type request struct {
+		Name     *string `json:"name" validate:"required|min:3"`
+		Active   *bool   `json:"active" validate:"required"`
+		Age      *int    `json:"age" validate:"nullable|required|min:18"`
+		Nickname *string `json:"nickname" validate:"omitempty|required|min:3"`
+		Limit    int     `json:"limit" validate:"omitempty|required"`
+	}
+	tests := []struct {
+		body   string
+		fields string
+	}{
+		{`{}`, "name,active"},
+		{`{"name":"","active":false,"nickname":""}`, "name"},
+		{`{"name":"Wood","active":true,"age":0,"nickname":"ab"}`, "age,nickname"},
+		{`{"name":"Wood","active":true,"age":null,"limit":0}`, ""},
+	}
+	for _, tt := range tests {
+		var req request
+		json.Unmarshal([]byte(tt.body), &req)
+		var fields string
+		if errs, ok := New().Validate(&req).(*ValidationErrors); ok {
+			fields = strings.Join(errs.Fields(), ",")
+		}
+		if fields != tt.fields {
+			t.Errorf("%s: got fields %q, want %q", tt.body, fields, tt.fields)
+		}
+	}
+	if err := New().ValidateMap(map[string]any{"age": nil}, map[string]string{"age": "nullable|required"}); err != nil {
+		t.Errorf("expected null to be allowed, got %v", err)
+	}
+}
+
+func TestPresenceRules(t *testing.T) {
+	type request struct {
+		Terms    bool     `json:"terms" validate:"present"`
+		Count    int      `json:"count" validate:"filled"`
+		Nickname string   `json:"nickname" validate:"sometimes|required|min:3"`
+		Admin    *bool    `json:"admin" validate:"missing"`
+		Tags     []string `json:"tags" validate:"present"`
+	}
+	handler := New().ValidateRequest(&request{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		body     string
+		code     int
+		response string
+	}{
+		{`{"terms":false,"tags":[]}`, http.StatusOK, ""},
+		{`{"terms":false,"count":3,"nickname":"ab","tags":null}`, http.StatusUnprocessableEntity, `{"status":false,"errors":{"nickname":"The nickname must be at least 3 characters."}}`},
+		{`{"terms":`, http.StatusBadRequest, `{"status":false,"message":"invalid JSON body: unexpected end of JSON input"}`},
+		{`{"count":"3"}`, http.StatusBadRequest, `{"status":false,"message":"invalid JSON body: json: cannot unmarshal string into Go struct field request.count of type int"}`},
+		{`{"count":0,"nickname":"","admin":null}`, http.StatusUnprocessableEntity, `{"status":false,"errors":{"admin":"The admin field must be missing.","count":"The count field must have a value.","nickname":"The nickname field is required.","tags":"The tags field must be present.","terms":"The terms field must be present."}}`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+		if rec.Code != tt.code || strings.TrimSpace(rec.Body.String()) != tt.response {
+			t.Errorf("%s: got %d %s", tt.body, rec.Code, rec.Body)
+		}
+	}
+
+	var errs *ValidationErrors
+	if !errors.As(New().Validate(&request{Admin: new(bool)}), &errs) || strings.Join(errs.Fields(), ",") != "terms,admin,tags" {
+		t.Errorf("expected zero values to be missing, got %v", errs)
+	}
+	type flags struct {
+		Admin bool `json:"admin" validate:"missing"`
+		Count int  `json:"count" validate:"filled"`
+	}
+	if err := New().Validate(&flags{}); err != nil {
+		t.Errorf("expected zero values to be missing without presence data, got %v", err)
+	}
+
+	payload := map[string]any{"terms": nil, "count": 0}
+	rules := map[string]string{"terms": "present", "count": "filled", "nickname": "present", "admin": "missing|sometimes|required"}
+	if !errors.As(New().ValidateMap(payload, rules), &errs) || strings.Join(errs.Fields(), ",") != "count,nickname" {
+		t.Errorf("expected map keys to be present, got %v", errs)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	var body []byte
+	var validated *TestDeepStruct
+	handler := New().ValidateRequest(&TestDeepStruct{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		validated, _ = Validated[*TestDeepStruct](r)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Wood"}`)))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"email":"The email field is required."`) {
+		t.Errorf("unexpected response: %s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	payload := `{"name":"Wood","email":"contact@mail.com"}`
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload)))
+	if rec.Code != http.StatusOK || string(body) != payload {
+		t.Errorf("expected next handler to read the body, got %d %q", rec.Code, body)
+	}
+	if validated == nil || validated.Name != "Wood" || validated.Email != "contact@mail.com" {
+		t.Errorf("expected next handler to get the decoded value, got %+v", validated)
+	}
+
+	var decoded map[string]any
+	handler = New().ValidateMapRequest(map[string]string{"email": "required|email"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decoded, _ = Validated[map[string]any](r)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload)))
+	if decoded["email"] != "contact@mail.com" {
+		t.Errorf("expected next handler to get the decoded map, got %v", decoded)
+	}
+}
+
+type ctxKey struct{}
+
+func TestValidateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := New().ValidateContext(ctx, &TestDeepStruct{})
+	if !errors.Is(err, ErrAborted) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected an aborted error, got %v", err)
+	}
+	var errs *ValidationErrors
+	if errors.As(err, &errs) {
+		t.Errorf("expected no validation errors, got %v", errs)
+	}
+
+	RegisterRule("ctx_value", func(value reflect.Value, _ []string, ctx *RuleContext) bool {
+		return ctx.Context().Value(ctxKey{}) == value.String()
+	}, RuleOptions{Kinds: []reflect.Kind{reflect.String}})
+	type request struct {
+		Token string `json:"token" validate:"ctx_value"`
+	}
+	ctx = context.WithValue(context.Background(), ctxKey{}, "secret")
+	if err := New().ValidateContext(ctx, &request{Token: "secret"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := New().ValidateContext(ctx, &request{Token: "guess"}); err == nil {
+		t.Error("expected a validation error")
+	}
+}
+
+func TestWithCollectAllErrors(t *testing.T) {
+	type request struct {
+		Password string   `json:"password" validate:"required|alpha_numeric|min:8|same:confirm"`
+		Username string   `json:"username" validate:"bail|alpha|min:8"`
+		Confirm  string   `json:"confirm"`
+		Tags     []string `json:"tags" validate:"alpha|min:3"`
+	}
+	payload := &request{Password: "pa$$", Username: "j0e", Confirm: "password", Tags: []string{"go", "r2"}}
+
+	err := New(WithCollectAllErrors()).Validate(payload)
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	count := make(map[string]int)
+	for _, fieldErr := range errs.All() {
+		count[fieldErr.Field]++
+	}
+	expected := map[string]int{"password": 3, "username": 1, "tags.0": 1, "tags.1": 2}
+	for field, n := range expected {
+		if count[field] != n {
+			t.Errorf("%s: got %d errors, want %d (%v)", field, count[field], n, errs)
+		}
+	}
+	body, _ := json.Marshal(errs)
+	var legacy map[string]any
+	json.Unmarshal(body, &legacy)
+	if msgs, _ := legacy["password"].([]any); len(msgs) != 3 {
+		t.Errorf("unexpected legacy layout: %s", body)
+	}
+
+	type dived struct {
+		Tags []string `json:"tags" validate:"min:2|dive|min:3"`
+	}
+	body, _ = json.Marshal(New(WithCollectAllErrors()).Validate(&dived{Tags: []string{"a"}}))
+	if expected := `{"tags":["The tags must have at least 2 items.","The tags (1) must be at least 3 characters."]}`; string(body) != expected {
+		t.Errorf("got %s, want %s", body, expected)
+	}
+	type nested struct {
+		Contacts []*TestDeepStruct         `json:"contacts" validate:"min:3|dive|required"`
+		Labels   map[string]TestDeepStruct `json:"labels" validate:"max:1"`
+	}
+	body, _ = json.Marshal(New(WithCollectAllErrors()).Validate(&nested{
+		Contacts: []*TestDeepStruct{{Name: "Wood", Email: "invalid"}},
+		Labels:   map[string]TestDeepStruct{"home": {Name: "Wood", Email: "contact@mail.com"}, "work": {Name: "Wood"}},
+	}))
+	if expected := `{"contacts":{"contacts":"The contacts must have at least 3 items.","contacts.0":{"email":"The email must be a valid email address."}},"labels":{"labels":"The labels must not have more than 1 items.","work":{"email":"The email field is required."}}}`; string(body) != expected {
+		t.Errorf("got %s, want %s", body, expected)
+	}
+
+	err = New().Validate(payload)
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if len(errs.All()) != 4 {
+		t.Errorf("expected one error per field or item by default, got %v", errs)
+	}
+}
+
+func TestVar(t *testing.T) {
+	validator := New()
+	if err := validator.Var("contact@mail.com", "required|email"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := validator.Var("GHA-123", "required|gh_card")
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected *ValidationErrors, got %v", err)
+	}
+	if fieldErr := errs.All()[0]; fieldErr.Rule != "gh_card" || fieldErr.Message != "The value must be a valid Ghana Card." {
+		t.Errorf("unexpected error %+v", fieldErr)
+	}
+	if err := validator.Var("", "required"); err == nil || err.Error() != "value: The value field is required." {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := validator.Var([]string{"+233265518694", "0241234567"}, "phone_with_code"); err == nil || !err.(*ValidationErrors).Has("value.1") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestVarWithValue(t *testing.T) {
+	validator := New()
+	if err := validator.VarWithValue("secret", "secret", "required|same:password"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := validator.VarWithValue("secret", "guess", "required|same:password"); err == nil {
+		t.Error("expected a validation error")
+	}
 }